@@ -0,0 +1,15 @@
+variable "api_password" {
+  type      = string
+  sensitive = true
+}
+
+data "http" "example" {
+  url = "https://example.com/api/status"
+
+  auth {
+    basic {
+      username = "terraform"
+      password = var.api_password
+    }
+  }
+}
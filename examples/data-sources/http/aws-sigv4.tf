@@ -0,0 +1,12 @@
+# The credentials are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY
+# and AWS_SESSION_TOKEN environment variables.
+data "http" "example" {
+  url = "https://abcdef1234.execute-api.us-east-1.amazonaws.com/prod/items"
+
+  auth {
+    aws_sigv4 {
+      region  = "us-east-1"
+      service = "execute-api"
+    }
+  }
+}
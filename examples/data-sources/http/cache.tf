@@ -0,0 +1,12 @@
+provider "http" {
+  # Responses younger than an hour are used without a request.
+  cache_ttl_ms = 3600000
+}
+
+data "http" "example" {
+  url = "https://checkpoint-api.hashicorp.com/v1/check/terraform"
+
+  # The response is revalidated with a conditional request once it is older
+  # than cache_ttl_ms, and only downloaded again if it has changed.
+  cache = true
+}
@@ -0,0 +1,18 @@
+data "http" "install_script" {
+  url = "https://releases.example.com/v1.2.3/install.sh"
+
+  # The checksums file lists the digest of install.sh, and a tampered
+  # download fails the data source.
+  expected_checksum = "https://releases.example.com/v1.2.3/SHA256SUMS"
+}
+
+resource "null_resource" "install" {
+  triggers = {
+    script = data.http.install_script.response_body_sha256
+  }
+
+  provisioner "local-exec" {
+    command     = data.http.install_script.response_body
+    interpreter = ["/bin/sh", "-c"]
+  }
+}
@@ -0,0 +1,7 @@
+data "http" "example" {
+  url = "https://api.example.com/v1/status"
+
+  client_cert_pem     = file("client.crt")
+  client_key_pem      = file("client.key")
+  client_key_password = var.client_key_password
+}
@@ -24,3 +24,14 @@ data "http" "example_post" {
   # Optional request body
   request_body = "request body"
 }
+
+# The following example shows how to issue an HTTP GET request supplying
+# query parameters, which are URL encoded.
+data "http" "example_query" {
+  url = "https://checkpoint-api.hashicorp.com/v1/check/terraform"
+
+  query_params = {
+    arch = ["amd64"]
+    os   = ["linux"]
+  }
+}
@@ -0,0 +1,11 @@
+data "http" "example" {
+  url = "https://checkpoint-api.hashicorp.com/v1/check/terraform"
+
+  request_headers = {
+    Accept = "application/json"
+  }
+}
+
+output "current_version" {
+  value = data.http.example.response_json.current_version
+}
@@ -0,0 +1,28 @@
+# The following example shows how to submit a form.
+data "http" "form" {
+  url    = "https://example.com/search"
+  method = "POST"
+
+  request_form = {
+    query = "terraform"
+    page  = "1"
+  }
+}
+
+# The following example shows how to upload a file along with form fields.
+data "http" "upload" {
+  url    = "https://example.com/upload"
+  method = "POST"
+
+  request_multipart {
+    fields = {
+      description = "Monthly report"
+    }
+
+    file {
+      name         = "report"
+      content_type = "text/csv"
+      source       = "${path.module}/report.csv"
+    }
+  }
+}
@@ -0,0 +1,17 @@
+variable "client_secret" {
+  type      = string
+  sensitive = true
+}
+
+data "http" "example" {
+  url = "https://api.example.com/v1/items"
+
+  auth {
+    oauth2_client_credentials {
+      token_url     = "https://auth.example.com/oauth2/token"
+      client_id     = "terraform"
+      client_secret = var.client_secret
+      scopes        = ["items:read"]
+    }
+  }
+}
@@ -0,0 +1,16 @@
+variable "proxy_password" {
+  type      = string
+  sensitive = true
+}
+
+data "http" "example" {
+  url = "https://checkpoint-api.hashicorp.com/v1/check/terraform"
+
+  # The host name is resolved by the proxy with the socks5h scheme.
+  proxy {
+    url      = "socks5h://proxy.example.com:1080"
+    username = "terraform"
+    password = var.proxy_password
+    no_proxy = [".internal.example.com"]
+  }
+}
@@ -0,0 +1,16 @@
+# Request the new backend by its production host name before the DNS records
+# are changed, so that the Host header and TLS server name are unchanged.
+data "http" "example" {
+  url = "https://api.example.com/health"
+
+  resolve = {
+    "api.example.com:443" = "192.0.2.10"
+  }
+
+  lifecycle {
+    postcondition {
+      condition     = self.remote_ip == "192.0.2.10"
+      error_message = "The request was not sent to the new backend."
+    }
+  }
+}
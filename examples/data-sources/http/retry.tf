@@ -0,0 +1,9 @@
+data "http" "example" {
+  url = "https://checkpoint-api.hashicorp.com/v1/check/terraform"
+
+  retry {
+    attempts     = 2
+    min_delay_ms = 500
+    max_delay_ms = 5000
+  }
+}
@@ -0,0 +1,9 @@
+# The certificate of the server is verified against legacy.example.com,
+# which only supports TLS 1.2 over HTTP/1.1.
+data "http" "example" {
+  url                = "https://192.0.2.10/status"
+  tls_server_name    = "legacy.example.com"
+  tls_max_version    = "1.2"
+  tls_cipher_suites  = ["TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"]
+  tls_alpn_protocols = ["http/1.1"]
+}
@@ -0,0 +1,9 @@
+# The host of the URL is only sent in the Host header.
+data "http" "docker_version" {
+  url              = "http://docker/version"
+  unix_socket_path = "/var/run/docker.sock"
+}
+
+output "docker_version" {
+  value = jsondecode(data.http.docker_version.response_body).Version
+}
@@ -0,0 +1,15 @@
+# The following example shows how to configure defaults which are shared
+# by all http data sources.
+provider "http" {
+  base_url = "https://checkpoint-api.hashicorp.com"
+
+  request_headers = {
+    Accept = "application/json"
+  }
+
+  request_timeout_ms = 30000
+}
+
+data "http" "example" {
+  url = "/v1/check/terraform"
+}
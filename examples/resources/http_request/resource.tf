@@ -0,0 +1,31 @@
+# The following example shows how to manage an item through a REST API,
+# using the id returned when it is created in the later requests.
+resource "http_request" "example" {
+  id_attribute = "id"
+
+  create {
+    url          = "https://api.example.com/v1/items"
+    request_body = jsonencode({ name = "example" })
+
+    request_headers = {
+      Content-Type = "application/json"
+    }
+  }
+
+  read {
+    url = "https://api.example.com/v1/items/{id}"
+  }
+
+  update {
+    url          = "https://api.example.com/v1/items/{id}"
+    request_body = jsonencode({ name = "example" })
+
+    request_headers = {
+      Content-Type = "application/json"
+    }
+  }
+
+  delete {
+    url = "https://api.example.com/v1/items/{id}"
+  }
+}
@@ -0,0 +1,509 @@
+package provider
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// authBlock returns the schema of the auth block, which holds the
+// credentials used to authenticate the request.
+func authBlock() schema.SingleNestedBlock {
+	credentialsAttributes := func() map[string]schema.Attribute {
+		return map[string]schema.Attribute{
+			"username": schema.StringAttribute{
+				Description: "The username.",
+				Optional:    true,
+			},
+			"password": schema.StringAttribute{
+				Description: "The password.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+		}
+	}
+
+	schemes := []string{"basic", "bearer", "digest", "oauth2_client_credentials", "aws_sigv4"}
+
+	// conflictsWithOthers makes each scheme conflict with all the others.
+	conflictsWithOthers := func(scheme string) validator.Object {
+		var expressions []path.Expression
+		for _, name := range schemes {
+			if name != scheme {
+				expressions = append(expressions, path.MatchRelative().AtParent().AtName(name))
+			}
+		}
+		return objectvalidator.ConflictsWith(expressions...)
+	}
+
+	return schema.SingleNestedBlock{
+		Description: "Authenticate the request. Exactly one of `basic`, `bearer`, `digest`, " +
+			"`oauth2_client_credentials` or `aws_sigv4` must be configured. " +
+			"The credentials are only sent to the scheme and host of `url`, and not to other hosts the " +
+			"request is redirected to, nor over `http` after a redirect from `https`. They take precedence over an `Authorization` header in `request_headers`.",
+		Blocks: map[string]schema.Block{
+			"basic": schema.SingleNestedBlock{
+				Description: "Authenticate using HTTP Basic authentication.",
+				Attributes:  credentialsAttributes(),
+				Validators: []validator.Object{
+					conflictsWithOthers("basic"),
+					objectvalidator.AlsoRequires(
+						path.MatchRelative().AtName("username"),
+						path.MatchRelative().AtName("password"),
+					),
+				},
+			},
+			"bearer": schema.SingleNestedBlock{
+				Description: "Authenticate using a bearer token, such as an OAuth 2.0 access token.",
+				Attributes: map[string]schema.Attribute{
+					"token": schema.StringAttribute{
+						Description: "The bearer token.",
+						Optional:    true,
+						Sensitive:   true,
+					},
+				},
+				Validators: []validator.Object{
+					conflictsWithOthers("bearer"),
+					objectvalidator.AlsoRequires(path.MatchRelative().AtName("token")),
+				},
+			},
+			"digest": schema.SingleNestedBlock{
+				Description: "Authenticate using HTTP Digest authentication. The request is sent without " +
+					"credentials first, and sent again in response to the challenge of the server. " +
+					"The `MD5`, `SHA-256` and `SHA-512-256` algorithms are supported, with a `qop` of `auth`.",
+				Attributes: credentialsAttributes(),
+				Validators: []validator.Object{
+					conflictsWithOthers("digest"),
+					objectvalidator.AlsoRequires(
+						path.MatchRelative().AtName("username"),
+						path.MatchRelative().AtName("password"),
+					),
+				},
+			},
+			"oauth2_client_credentials": schema.SingleNestedBlock{
+				Description: "Authenticate using an access token obtained with the OAuth 2.0 client credentials grant. " +
+					"The token is requested by the provider and sent as a bearer token, so that it is not stored in the state. " +
+					"Tokens are shared by the data sources of a provider instance with the same configuration, " +
+					"and requested again when they expire.",
+				Attributes: map[string]schema.Attribute{
+					"token_url": schema.StringAttribute{
+						Description: "The URL of the token endpoint.",
+						Optional:    true,
+					},
+					"client_id": schema.StringAttribute{
+						Description: "The client identifier.",
+						Optional:    true,
+					},
+					"client_secret": schema.StringAttribute{
+						Description: "The client secret.",
+						Optional:    true,
+						Sensitive:   true,
+					},
+					"scopes": schema.ListAttribute{
+						Description: "The scopes requested for the token.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+					"audience": schema.StringAttribute{
+						Description: "The `audience` parameter of the token request, used by some token endpoints " +
+							"to select the API the token is issued for.",
+						Optional: true,
+					},
+					"extra_params": schema.MapAttribute{
+						Description: "Additional parameters of the token request.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+				Validators: []validator.Object{
+					conflictsWithOthers("oauth2_client_credentials"),
+					objectvalidator.AlsoRequires(
+						path.MatchRelative().AtName("token_url"),
+						path.MatchRelative().AtName("client_id"),
+						path.MatchRelative().AtName("client_secret"),
+					),
+				},
+			},
+			"aws_sigv4": schema.SingleNestedBlock{
+				Description: "Sign the request with AWS Signature Version 4, as required by AWS APIs and " +
+					"API Gateway endpoints using IAM authorization. The signature covers the request body. " +
+					"Credentials which are not configured are read from the `AWS_ACCESS_KEY_ID`, " +
+					"`AWS_SECRET_ACCESS_KEY` and `AWS_SESSION_TOKEN` environment variables.",
+				Attributes: map[string]schema.Attribute{
+					"region": schema.StringAttribute{
+						Description: "The AWS region of the endpoint. Defaults to the `AWS_REGION` " +
+							"or `AWS_DEFAULT_REGION` environment variable.",
+						Optional: true,
+					},
+					"service": schema.StringAttribute{
+						Description: "The signing name of the AWS service, such as `execute-api` for API Gateway.",
+						Optional:    true,
+					},
+					"access_key": schema.StringAttribute{
+						Description: "The AWS access key ID.",
+						Optional:    true,
+						Validators: []validator.String{
+							stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("secret_key")),
+						},
+					},
+					"secret_key": schema.StringAttribute{
+						Description: "The AWS secret access key.",
+						Optional:    true,
+						Sensitive:   true,
+						Validators: []validator.String{
+							stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("access_key")),
+						},
+					},
+					"session_token": schema.StringAttribute{
+						Description: "The AWS session token of temporary credentials.",
+						Optional:    true,
+						Sensitive:   true,
+						Validators: []validator.String{
+							stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("access_key")),
+						},
+					},
+				},
+				Validators: []validator.Object{
+					conflictsWithOthers("aws_sigv4"),
+					objectvalidator.AlsoRequires(path.MatchRelative().AtName("service")),
+				},
+			},
+		},
+	}
+}
+
+type authModel struct {
+	Basic                   *credentialsModel             `tfsdk:"basic"`
+	Bearer                  *bearerModel                  `tfsdk:"bearer"`
+	Digest                  *credentialsModel             `tfsdk:"digest"`
+	OAuth2ClientCredentials *oauth2ClientCredentialsModel `tfsdk:"oauth2_client_credentials"`
+	AWSSigV4                *awsSigV4Model                `tfsdk:"aws_sigv4"`
+}
+
+type credentialsModel struct {
+	Username types.String `tfsdk:"username"`
+	Password types.String `tfsdk:"password"`
+}
+
+type bearerModel struct {
+	Token types.String `tfsdk:"token"`
+}
+
+// authTransport authenticates the requests sent to a single host with a
+// single scheme.
+type authTransport struct {
+	base   http.RoundTripper
+	scheme string
+	host   string
+
+	// tokenBase sends the OAuth 2.0 token requests, which go to another
+	// server than the host.
+	tokenBase http.RoundTripper
+
+	model  authModel
+	oauth2 *oauth2ClientCredentials
+	tokens *oauth2TokenCache
+	sigv4  *awsSigV4Signer
+}
+
+// newAuthTransport wraps the transport so that requests with the scheme and
+// host of target are authenticated according to the auth block, which may be
+// nil when the block is not configured. OAuth 2.0 tokens are obtained through
+// the tokens cache, with requests sent by tokenBase.
+func newAuthTransport(ctx context.Context, base, tokenBase http.RoundTripper, target *url.URL, model *authModel, tokens *oauth2TokenCache) (http.RoundTripper, error) {
+	if model == nil {
+		return base, nil
+	}
+
+	t := &authTransport{
+		base:      base,
+		scheme:    target.Scheme,
+		host:      target.Host,
+		tokenBase: tokenBase,
+		model:     *model,
+		tokens:    tokens,
+	}
+
+	switch {
+	case model.OAuth2ClientCredentials != nil:
+		config, err := newOAuth2ClientCredentials(ctx, model.OAuth2ClientCredentials)
+		if err != nil {
+			return nil, err
+		}
+		t.oauth2 = config
+	case model.AWSSigV4 != nil:
+		signer, err := newAWSSigV4Signer(model.AWSSigV4)
+		if err != nil {
+			return nil, err
+		}
+		t.sigv4 = signer
+	case model.Basic == nil && model.Bearer == nil && model.Digest == nil:
+		return nil, errors.New("one of basic, bearer, digest, oauth2_client_credentials or aws_sigv4 must be configured")
+	}
+
+	return t, nil
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The credentials are not sent in the clear after a redirect from https
+	// to http on the same host.
+	if req.URL.Host != t.host || req.URL.Scheme != t.scheme {
+		return t.base.RoundTrip(req)
+	}
+
+	switch {
+	case t.model.Basic != nil:
+		req = req.Clone(req.Context())
+		req.SetBasicAuth(t.model.Basic.Username.ValueString(), t.model.Basic.Password.ValueString())
+	case t.model.Bearer != nil:
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+t.model.Bearer.Token.ValueString())
+	case t.model.Digest != nil:
+		return t.roundTripDigest(req)
+	case t.oauth2 != nil:
+		token, err := t.tokens.token(req.Context(), &http.Client{Transport: t.tokenBase}, t.oauth2)
+		if err != nil {
+			return nil, fmt.Errorf("obtaining OAuth 2.0 token: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+token.accessToken)
+	case t.sigv4 != nil:
+		req = req.Clone(req.Context())
+		if err := t.sigv4.sign(req, time.Now()); err != nil {
+			return nil, fmt.Errorf("signing request: %w", err)
+		}
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// roundTripDigest sends the request without credentials and, if the server
+// responds with a Digest challenge, sends it again with the response to the
+// challenge.
+func (t *authTransport) roundTripDigest(req *http.Request) (*http.Response, error) {
+	// The first attempt consumes the body, so a copy is needed to replay it.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	first := req.Clone(req.Context())
+	first.Header.Del("Authorization")
+
+	response, err := t.base.RoundTrip(first)
+	if err != nil || response.StatusCode != http.StatusUnauthorized {
+		return response, err
+	}
+
+	var challenge map[string]string
+	for _, c := range parseChallenges(response.Header.Values("WWW-Authenticate")) {
+		if strings.EqualFold(c.scheme, "Digest") && digestHash(c.params["algorithm"]) != nil {
+			challenge = c.params
+			break
+		}
+	}
+
+	if challenge == nil {
+		return response, nil
+	}
+
+	cnonce := make([]byte, 16)
+	if _, err := rand.Read(cnonce); err != nil {
+		return nil, err
+	}
+
+	authorization, err := digestAuthorization(
+		challenge,
+		t.model.Digest.Username.ValueString(),
+		t.model.Digest.Password.ValueString(),
+		req.Method,
+		req.URL.RequestURI(),
+		hex.EncodeToString(cnonce),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("digest authentication: %w", err)
+	}
+
+	discardResponseBody(response)
+
+	second := req.Clone(req.Context())
+	if req.GetBody != nil {
+		second.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+	second.Header.Set("Authorization", authorization)
+
+	return t.base.RoundTrip(second)
+}
+
+// challenge is an authentication challenge of a WWW-Authenticate header.
+type challenge struct {
+	scheme string
+	params map[string]string
+}
+
+// parseChallenges parses the values of WWW-Authenticate headers, each of
+// which may hold several comma separated challenges.
+// See https://datatracker.ietf.org/doc/html/rfc7235#section-4.1
+func parseChallenges(values []string) []challenge {
+	var challenges []challenge
+
+	for _, value := range values {
+		s := value
+		for {
+			s = strings.TrimLeft(s, " \t,")
+			if s == "" {
+				break
+			}
+
+			end := strings.IndexAny(s, " \t,=")
+			if end < 0 {
+				end = len(s)
+			}
+			token := s[:end]
+			s = strings.TrimLeft(s[end:], " \t")
+
+			if !strings.HasPrefix(s, "=") || len(challenges) == 0 {
+				challenges = append(challenges, challenge{scheme: token, params: map[string]string{}})
+				continue
+			}
+
+			var param string
+			param, s = parseParamValue(strings.TrimLeft(s[1:], " \t"))
+			challenges[len(challenges)-1].params[strings.ToLower(token)] = param
+		}
+	}
+
+	return challenges
+}
+
+// parseParamValue parses a token or quoted string at the start of s, and
+// returns it along with the remainder of s.
+func parseParamValue(s string) (string, string) {
+	if !strings.HasPrefix(s, `"`) {
+		end := strings.IndexAny(s, " \t,")
+		if end < 0 {
+			end = len(s)
+		}
+		return s[:end], s[end:]
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), s[i+1:]
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String(), ""
+}
+
+// digestHash returns the hash function of a Digest algorithm, or nil if the
+// algorithm is not supported. The "-sess" variants use the same function.
+func digestHash(algorithm string) func() hash.Hash {
+	switch strings.TrimSuffix(strings.ToUpper(algorithm), "-SESS") {
+	case "", "MD5":
+		return md5.New
+	case "SHA-256":
+		return sha256.New
+	case "SHA-512-256":
+		return sha512.New512_256
+	}
+
+	return nil
+}
+
+// digestAuthorization computes the Authorization header value answering a
+// Digest challenge.
+// See https://datatracker.ietf.org/doc/html/rfc7616#section-3.4
+func digestAuthorization(challenge map[string]string, username, password, method, uri, cnonce string) (string, error) {
+	algorithm := challenge["algorithm"]
+	newHash := digestHash(algorithm)
+	if newHash == nil {
+		return "", fmt.Errorf("unsupported algorithm %q", algorithm)
+	}
+
+	h := func(parts ...string) string {
+		hash := newHash()
+		hash.Write([]byte(strings.Join(parts, ":")))
+		return hex.EncodeToString(hash.Sum(nil))
+	}
+
+	realm, nonce := challenge["realm"], challenge["nonce"]
+
+	var qop string
+	if offered, ok := challenge["qop"]; ok {
+		for _, option := range strings.Split(offered, ",") {
+			if strings.TrimSpace(option) == "auth" {
+				qop = "auth"
+			}
+		}
+		if qop == "" {
+			return "", fmt.Errorf("unsupported qop %q", offered)
+		}
+	}
+
+	ha1 := h(username, realm, password)
+	if strings.HasSuffix(strings.ToUpper(algorithm), "-SESS") {
+		ha1 = h(ha1, nonce, cnonce)
+	}
+	ha2 := h(method, uri)
+
+	const nc = "00000001"
+
+	var response string
+	if qop == "" {
+		response = h(ha1, nonce, ha2)
+	} else {
+		response = h(ha1, nonce, nc, cnonce, qop, ha2)
+	}
+
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+	}
+
+	params := []string{
+		"username=" + quote(username),
+		"realm=" + quote(realm),
+		"nonce=" + quote(nonce),
+		"uri=" + quote(uri),
+	}
+	if algorithm != "" {
+		params = append(params, "algorithm="+algorithm)
+	}
+	params = append(params, "response="+quote(response))
+	if opaque, ok := challenge["opaque"]; ok {
+		params = append(params, "opaque="+quote(opaque))
+	}
+	if qop != "" {
+		params = append(params, "qop="+qop, "nc="+nc, "cnonce="+quote(cnonce))
+	}
+
+	return "Digest " + strings.Join(params, ", "), nil
+}
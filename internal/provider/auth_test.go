@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseChallenges(t *testing.T) {
+	testCases := map[string]struct {
+		values   []string
+		expected []challenge
+	}{
+		"digest": {
+			values: []string{`Digest realm="testrealm@host.com", qop="auth,auth-int", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", opaque="5ccc069c403ebaf9f0171e9517f40e41"`},
+			expected: []challenge{
+				{
+					scheme: "Digest",
+					params: map[string]string{
+						"realm":  "testrealm@host.com",
+						"qop":    "auth,auth-int",
+						"nonce":  "dcd98b7102dd2f0e8b11d0f600bfb0c093",
+						"opaque": "5ccc069c403ebaf9f0171e9517f40e41",
+					},
+				},
+			},
+		},
+		"multiple": {
+			values: []string{`Basic realm="simple", Digest realm="a \"quoted\" realm", algorithm=SHA-256`, `Bearer`},
+			expected: []challenge{
+				{scheme: "Basic", params: map[string]string{"realm": "simple"}},
+				{scheme: "Digest", params: map[string]string{"realm": `a "quoted" realm`, "algorithm": "SHA-256"}},
+				{scheme: "Bearer", params: map[string]string{}},
+			},
+		},
+		"empty": {
+			values: []string{""},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := parseChallenges(testCase.values)
+
+			if !reflect.DeepEqual(actual, testCase.expected) {
+				t.Errorf("expected %v, got %v", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDigestAuthorization(t *testing.T) {
+	testCases := map[string]struct {
+		challenge map[string]string
+		username  string
+		password  string
+		cnonce    string
+		expected  string
+	}{
+		// See https://datatracker.ietf.org/doc/html/rfc2617#section-3.5
+		"rfc2617": {
+			challenge: map[string]string{
+				"realm":  "testrealm@host.com",
+				"qop":    "auth,auth-int",
+				"nonce":  "dcd98b7102dd2f0e8b11d0f600bfb0c093",
+				"opaque": "5ccc069c403ebaf9f0171e9517f40e41",
+			},
+			username: "Mufasa",
+			password: "Circle Of Life",
+			cnonce:   "0a4f113b",
+			expected: `Digest username="Mufasa", realm="testrealm@host.com", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", uri="/dir/index.html", response="6629fae49393a05397450978507c4ef1", opaque="5ccc069c403ebaf9f0171e9517f40e41", qop=auth, nc=00000001, cnonce="0a4f113b"`,
+		},
+		// See https://datatracker.ietf.org/doc/html/rfc7616#section-3.9.1
+		"rfc7616-sha-256": {
+			challenge: map[string]string{
+				"realm":     "http-auth@example.org",
+				"qop":       "auth, auth-int",
+				"algorithm": "SHA-256",
+				"nonce":     "7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v",
+				"opaque":    "FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS",
+			},
+			username: "Mufasa",
+			password: "Circle of Life",
+			cnonce:   "f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ",
+			expected: `Digest username="Mufasa", realm="http-auth@example.org", nonce="7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v", uri="/dir/index.html", algorithm=SHA-256, response="753927fa0e85d155564e2e272a28d1802ca10daf4496794697cf8db5856cb6c1", opaque="FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS", qop=auth, nc=00000001, cnonce="f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ"`,
+		},
+		"rfc7616-md5": {
+			challenge: map[string]string{
+				"realm":     "http-auth@example.org",
+				"qop":       "auth, auth-int",
+				"algorithm": "MD5",
+				"nonce":     "7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v",
+				"opaque":    "FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS",
+			},
+			username: "Mufasa",
+			password: "Circle of Life",
+			cnonce:   "f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ",
+			expected: `Digest username="Mufasa", realm="http-auth@example.org", nonce="7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v", uri="/dir/index.html", algorithm=MD5, response="8ca523f5e9506fed4657c9700eebdbec", opaque="FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS", qop=auth, nc=00000001, cnonce="f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := digestAuthorization(testCase.challenge, testCase.username, testCase.password, "GET", "/dir/index.html", testCase.cnonce)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if actual != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+// cacheKeyAttributes are the data source attributes which change the response
+// to a request, besides the request itself, such as the credentials sent with
+// it or the server it is sent to. The response cached for one configuration is
+// never used for another.
+var cacheKeyAttributes = []string{
+	"auth",
+	"ca_cert_pem",
+	"insecure",
+	"client_cert_pem",
+	"client_key_pem",
+	"client_pkcs12_base64",
+	"client_key_password",
+	"tls_min_version",
+	"tls_max_version",
+	"tls_cipher_suites",
+	"tls_server_name",
+	"tls_alpn_protocols",
+	"resolve",
+	"dns_servers",
+	"unix_socket_path",
+	"proxy",
+	"follow_redirects",
+	"max_redirects",
+	"allow_cross_host_redirects",
+	"allow_insecure_redirects",
+}
+
+// responseCache stores responses on disk, so that unchanged responses are
+// served from the cache after a conditional request instead of being
+// downloaded again.
+type responseCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// newResponseCache returns a cache in dir, or in the user cache directory if
+// dir is empty. Entries younger than ttl are used without a request.
+func newResponseCache(dir string, ttl time.Duration) *responseCache {
+	if dir == "" {
+		if userCacheDir, err := os.UserCacheDir(); err == nil {
+			dir = filepath.Join(userCacheDir, "terraform-provider-http")
+		}
+	}
+
+	return &responseCache{
+		dir: dir,
+		ttl: ttl,
+	}
+}
+
+// cacheEntry is a response stored in the cache.
+type cacheEntry struct {
+	// URL is the URL of the final request, after following any redirects.
+	URL string `json:"url"`
+
+	// RedirectChain is the URLs requested, in order, ending with URL.
+	RedirectChain []string `json:"redirect_chain,omitempty"`
+
+	Status     string      `json:"status"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"stored_at"`
+
+	// RemoteIP is the IP address the response was received from, if any.
+	RemoteIP string `json:"remote_ip,omitempty"`
+}
+
+// key identifies the request in the cache. It is hashed from the method, URL,
+// headers and body of the request, and the settings returned by
+// cacheSettings, so that the cache file names do not hold any of them.
+func (c *responseCache) key(req *http.Request, body []byte, settings []string) string {
+	hash := sha256.New()
+
+	write := func(s string) {
+		hash.Write([]byte(s))
+		hash.Write([]byte{0})
+	}
+
+	write(req.Method)
+	write(req.URL.String())
+
+	for _, setting := range settings {
+		write(setting)
+	}
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		write(name)
+		write(strings.Join(req.Header[name], "\n"))
+	}
+
+	write(string(body))
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// cacheSettings returns the values of the cacheKeyAttributes in the data
+// source configuration, which are hashed into the cache key.
+func cacheSettings(ctx context.Context, config tfsdk.Config) ([]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	settings := make([]string, 0, len(cacheKeyAttributes))
+	for _, name := range cacheKeyAttributes {
+		var value attr.Value
+		diags.Append(config.GetAttribute(ctx, path.Root(name), &value)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		settings = append(settings, name+"="+value.String())
+	}
+
+	return settings, diags
+}
+
+func (c *responseCache) path(key string) (string, error) {
+	if c.dir == "" {
+		return "", errors.New("no user cache directory found, set the provider cache_dir")
+	}
+
+	return filepath.Join(c.dir, key+".json"), nil
+}
+
+// load returns the entry stored for the key, or nil if there is none.
+func (c *responseCache) load(key string) (*cacheEntry, error) {
+	path, err := c.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		// A corrupt entry is replaced by the next response.
+		return nil, nil
+	}
+
+	return &entry, nil
+}
+
+// store writes the entry for the key. The entry is written to a temporary
+// file first, so that concurrent readers never see a partial entry.
+func (c *responseCache) store(key string, entry *cacheEntry) error {
+	path, err := c.path(key)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// fresh reports whether the entry can be used without a request.
+func (c *responseCache) fresh(entry *cacheEntry) bool {
+	return c.ttl > 0 && time.Since(entry.StoredAt) < c.ttl
+}
+
+// isCacheable reports whether the response can be stored. Only complete
+// 200 OK responses are stored, and only if they can be revalidated with a
+// conditional request or are used without one for the cache TTL.
+func (c *responseCache) isCacheable(response *http.Response, truncated bool) bool {
+	if response.StatusCode != http.StatusOK || truncated {
+		return false
+	}
+
+	for _, directive := range strings.Split(response.Header.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return false
+		}
+	}
+
+	return c.ttl > 0 || response.Header.Get("ETag") != "" || response.Header.Get("Last-Modified") != ""
+}
+
+// newCacheEntry returns the entry storing the response, whose body has
+// already been read, received from the remote IP address.
+func newCacheEntry(response *http.Response, body []byte, remoteIP string) *cacheEntry {
+	return &cacheEntry{
+		URL:           response.Request.URL.String(),
+		RedirectChain: redirectChain(response),
+		Status:        response.Status,
+		StatusCode:    response.StatusCode,
+		Header:        response.Header.Clone(),
+		Body:          body,
+		StoredAt:      time.Now(),
+		RemoteIP:      remoteIP,
+	}
+}
+
+// setConditionalHeaders makes the request conditional on the response having
+// changed since the entry was stored.
+func (e *cacheEntry) setConditionalHeaders(req *http.Request) {
+	if etag := e.Header.Get("ETag"); etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	if lastModified := e.Header.Get("Last-Modified"); lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+}
+
+// revalidate updates the entry with a 304 Not Modified response, which may
+// carry new header values such as an ETag, received from the remote IP
+// address.
+// See https://www.rfc-editor.org/rfc/rfc9111#section-4.3.4
+func (e *cacheEntry) revalidate(response *http.Response, remoteIP string) {
+	for name, values := range response.Header {
+		if name == "Content-Length" {
+			continue
+		}
+		e.Header[name] = values
+	}
+
+	e.StoredAt = time.Now()
+	e.RemoteIP = remoteIP
+}
+
+// response returns the stored response as the response to the request.
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        e.Status,
+		StatusCode:    e.StatusCode,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// request returns a copy of the request to the URL of the entry, standing for
+// the request of an entry used without a request. Like the request of a
+// client response, it links to the requests of the redirects followed to the
+// URL, which entries stored without a redirect chain do not have.
+func (e *cacheEntry) request(req *http.Request) *http.Request {
+	chain := e.RedirectChain
+	if len(chain) == 0 {
+		chain = []string{e.URL}
+	}
+
+	var previous *http.Request
+	for _, rawURL := range chain {
+		clone := req.Clone(req.Context())
+
+		if u, err := url.Parse(rawURL); err == nil {
+			clone.URL = u
+		}
+
+		if previous != nil {
+			clone.Response = &http.Response{Request: previous}
+		}
+		previous = clone
+	}
+
+	return previous
+}
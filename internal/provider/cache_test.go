@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestResponseCacheKey(t *testing.T) {
+	cache := newResponseCache(t.TempDir(), 0)
+
+	newRequest := func(method, rawURL string, header http.Header) *http.Request {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &http.Request{Method: method, URL: u, Header: header}
+	}
+
+	key := cache.key(newRequest("GET", "https://example.com/a", http.Header{"Accept": {"text/plain"}}), nil, nil)
+
+	testCases := map[string]struct {
+		request  *http.Request
+		body     []byte
+		settings []string
+		expected bool
+	}{
+		"same": {
+			request:  newRequest("GET", "https://example.com/a", http.Header{"Accept": {"text/plain"}}),
+			expected: true,
+		},
+		"method": {
+			request: newRequest("POST", "https://example.com/a", http.Header{"Accept": {"text/plain"}}),
+		},
+		"url": {
+			request: newRequest("GET", "https://example.com/b", http.Header{"Accept": {"text/plain"}}),
+		},
+		"header": {
+			request: newRequest("GET", "https://example.com/a", http.Header{"Accept": {"application/json"}}),
+		},
+		"body": {
+			request: newRequest("GET", "https://example.com/a", http.Header{"Accept": {"text/plain"}}),
+			body:    []byte("body"),
+		},
+		"settings": {
+			request:  newRequest("GET", "https://example.com/a", http.Header{"Accept": {"text/plain"}}),
+			settings: []string{`auth={"basic":{"password":"secret","username":"user"}}`},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := cache.key(testCase.request, testCase.body, testCase.settings) == key
+
+			if actual != testCase.expected {
+				t.Errorf("expected same key %t, got %t", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestResponseCacheStore(t *testing.T) {
+	cache := newResponseCache(t.TempDir(), time.Hour)
+
+	entry, err := cache.load("missing")
+	if err != nil || entry != nil {
+		t.Fatalf("expected no entry, got %v, %v", entry, err)
+	}
+
+	u, _ := url.Parse("https://example.com/final")
+	response := &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Etag": {`"v1"`}},
+		Request:    &http.Request{Method: "GET", URL: u},
+	}
+
+	if !cache.isCacheable(response, false) {
+		t.Fatal("expected response to be cacheable")
+	}
+
+	if err := cache.store("key", newCacheEntry(response, []byte("hello"), "192.0.2.1")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	entry, err = cache.load("key")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !cache.fresh(entry) {
+		t.Error("expected entry to be fresh")
+	}
+
+	if entry.RemoteIP != "192.0.2.1" {
+		t.Errorf("expected remote IP 192.0.2.1, got %q", entry.RemoteIP)
+	}
+
+	request := &http.Request{Header: http.Header{}}
+	entry.setConditionalHeaders(request)
+	if request.Header.Get("If-None-Match") != `"v1"` {
+		t.Errorf("expected If-None-Match %q, got %q", `"v1"`, request.Header.Get("If-None-Match"))
+	}
+
+	entry.revalidate(&http.Response{Header: http.Header{"Etag": {`"v2"`}, "Content-Length": {"0"}}}, "192.0.2.2")
+
+	cached := entry.response(entry.request(&http.Request{Method: "GET", URL: &url.URL{}}))
+	body, _ := io.ReadAll(cached.Body)
+
+	if cached.StatusCode != http.StatusOK || string(body) != "hello" || cached.ContentLength != 5 ||
+		cached.Header.Get("ETag") != `"v2"` || cached.Request.URL.String() != "https://example.com/final" || entry.RemoteIP != "192.0.2.2" {
+		t.Errorf("unexpected cached response %d %q %d %v %s %s",
+			cached.StatusCode, body, cached.ContentLength, cached.Header, cached.Request.URL, entry.RemoteIP)
+	}
+}
+
+func TestResponseCacheIsCacheable(t *testing.T) {
+	testCases := map[string]struct {
+		statusCode int
+		header     http.Header
+		truncated  bool
+		ttl        time.Duration
+		expected   bool
+	}{
+		"etag": {
+			statusCode: http.StatusOK,
+			header:     http.Header{"Etag": {`"v1"`}},
+			expected:   true,
+		},
+		"last-modified": {
+			statusCode: http.StatusOK,
+			header:     http.Header{"Last-Modified": {"Wed, 21 Oct 2015 07:28:00 GMT"}},
+			expected:   true,
+		},
+		"no-validator": {
+			statusCode: http.StatusOK,
+			header:     http.Header{},
+		},
+		"no-validator-ttl": {
+			statusCode: http.StatusOK,
+			header:     http.Header{},
+			ttl:        time.Minute,
+			expected:   true,
+		},
+		"no-store": {
+			statusCode: http.StatusOK,
+			header:     http.Header{"Etag": {`"v1"`}, "Cache-Control": {"private, no-store"}},
+		},
+		"truncated": {
+			statusCode: http.StatusOK,
+			header:     http.Header{"Etag": {`"v1"`}},
+			truncated:  true,
+		},
+		"not-ok": {
+			statusCode: http.StatusNotFound,
+			header:     http.Header{"Etag": {`"v1"`}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := newResponseCache(t.TempDir(), testCase.ttl)
+			response := &http.Response{StatusCode: testCase.statusCode, Header: testCase.header}
+
+			actual := cache.isCacheable(response, testCase.truncated)
+
+			if actual != testCase.expected {
+				t.Errorf("expected %t, got %t", testCase.expected, actual)
+			}
+		})
+	}
+}
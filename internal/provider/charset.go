@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// responseCharset returns the charset parameter of the Content-Type header,
+// or an empty string if there is none.
+func responseCharset(contentType string) string {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	return params["charset"]
+}
+
+// isCharsetUTF8 reports whether a body in the named charset can be used as is.
+// An empty name is treated as UTF-8, as was the case before charsets were
+// supported.
+func isCharsetUTF8(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "utf-8", "utf8", "us-ascii":
+		return true
+	}
+
+	return false
+}
+
+// decodeCharset converts a body in the named charset to UTF-8. The names and
+// aliases of the WHATWG Encoding Standard are supported, as used by browsers.
+// A byte order mark at the start of the body takes precedence over the name.
+func decodeCharset(body []byte, name string) ([]byte, error) {
+	if isCharsetUTF8(name) {
+		return body, nil
+	}
+
+	encoding, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %q", name)
+	}
+
+	decoded, _, err := transform.Bytes(unicode.BOMOverride(encoding.NewDecoder()), body)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", name, err)
+	}
+
+	return decoded, nil
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestDecodeCharset(t *testing.T) {
+	testCases := map[string]struct {
+		body        []byte
+		charset     string
+		expected    string
+		expectError bool
+	}{
+		"none": {
+			body:     []byte("caf\xc3\xa9"),
+			expected: "café",
+		},
+		"utf-8": {
+			body:     []byte("caf\xc3\xa9"),
+			charset:  "UTF-8",
+			expected: "café",
+		},
+		"iso-8859-1": {
+			body:     []byte("caf\xe9"),
+			charset:  "ISO-8859-1",
+			expected: "café",
+		},
+		"iso-8859-15": {
+			body:     []byte("\xa4 5"),
+			charset:  "iso-8859-15",
+			expected: "€ 5",
+		},
+		"windows-1251": {
+			body:     []byte("\xcf\xf0\xe8\xe2\xe5\xf2"),
+			charset:  "windows-1251",
+			expected: "Привет",
+		},
+		"shift_jis": {
+			body:     []byte("\x93\xfa\x96\x7b"),
+			charset:  "Shift_JIS",
+			expected: "日本",
+		},
+		"utf-16-bom": {
+			body:     []byte("\xfe\xff\x00c\x00a\x00f\x00\xe9"),
+			charset:  "UTF-16",
+			expected: "café",
+		},
+		"utf-16le": {
+			body:     []byte("c\x00a\x00f\x00\xe9\x00"),
+			charset:  "utf-16le",
+			expected: "café",
+		},
+		"unsupported": {
+			body:        []byte("caf\xe9"),
+			charset:     "x-unknown",
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := decodeCharset(testCase.body, testCase.charset)
+
+			if testCase.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got %q", actual)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if string(actual) != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// maxChecksumFileBytes limits the size of a checksums file, which lists one
+// digest per line.
+const maxChecksumFileBytes = 1024 * 1024
+
+// checksumAlgorithms are the algorithms supported by expected_checksum,
+// which are those of the response_body_* digest attributes.
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// expectedChecksumPattern matches the forms of expected_checksum, either an
+// algorithm and digest such as sha256:2c26b4... or the URL of a checksums
+// file.
+var expectedChecksumPattern = regexp.MustCompile(`^((md5|sha256|sha512):[0-9a-fA-F]+|https?://.+)$`)
+
+// hexDigest returns the hex encoded digest of the data.
+func hexDigest(algorithm string, data []byte) string {
+	h := checksumAlgorithms[algorithm]()
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// expectedChecksum is the digest the response body must match.
+type expectedChecksum struct {
+	algorithm string
+	digest    string
+}
+
+// parseExpectedChecksum parses an expected_checksum in algorithm:digest form.
+func parseExpectedChecksum(value string) (*expectedChecksum, error) {
+	algorithm, digest, ok := strings.Cut(value, ":")
+	if !ok {
+		return nil, fmt.Errorf("checksum %q is not in algorithm:digest form", value)
+	}
+
+	algorithm = strings.ToLower(algorithm)
+	newHash, ok := checksumAlgorithms[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported checksum algorithm %q", algorithm)
+	}
+
+	decoded, err := hex.DecodeString(digest)
+	if err != nil {
+		return nil, fmt.Errorf("checksum digest %q is not hex encoded", digest)
+	}
+
+	if len(decoded) != newHash().Size() {
+		return nil, fmt.Errorf("%s checksum digest must be %d hex characters, got %d",
+			algorithm, 2*newHash().Size(), len(digest))
+	}
+
+	return &expectedChecksum{
+		algorithm: algorithm,
+		digest:    strings.ToLower(digest),
+	}, nil
+}
+
+// checksumAlgorithmForDigest returns the algorithm whose digests have the
+// length of the hex encoded digest.
+func checksumAlgorithmForDigest(digest string) (string, error) {
+	for algorithm, newHash := range checksumAlgorithms {
+		if len(digest) == 2*newHash().Size() {
+			return algorithm, nil
+		}
+	}
+
+	return "", fmt.Errorf("digest %q does not match the length of a supported checksum algorithm", digest)
+}
+
+// bsdChecksumLine matches a line of a checksums file in the BSD format, such
+// as written by sha256sum --tag.
+var bsdChecksumLine = regexp.MustCompile(`^(MD5|SHA256|SHA512) \((.+)\) = ([0-9a-fA-F]+)$`)
+
+// parseChecksumFile returns the checksum of the named file from a checksums
+// file in the format written by sha256sum and similar tools, either
+// "<digest>  <name>" or "<algorithm> (<name>) = <digest>". A file with a
+// single line holding only a digest applies to any name.
+func parseChecksumFile(data []byte, name string) (*expectedChecksum, error) {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(lines) == 1 && !strings.ContainsAny(lines[0], " \t") {
+		return checksumFromDigest(lines[0])
+	}
+
+	for _, line := range lines {
+		if match := bsdChecksumLine.FindStringSubmatch(line); match != nil {
+			if match[2] == name {
+				return parseExpectedChecksum(strings.ToLower(match[1]) + ":" + match[3])
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		// A leading * marks a file read in binary mode.
+		if strings.TrimPrefix(fields[1], "*") == name {
+			return checksumFromDigest(fields[0])
+		}
+	}
+
+	return nil, fmt.Errorf("no checksum found for %q", name)
+}
+
+func checksumFromDigest(digest string) (*expectedChecksum, error) {
+	algorithm, err := checksumAlgorithmForDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseExpectedChecksum(algorithm + ":" + digest)
+}
+
+// newExpectedChecksum returns the checksum given by expected_checksum, which
+// is downloaded from a checksums file when it is a URL. The name of the file
+// looked up in the checksums file is taken from the request URL.
+func newExpectedChecksum(ctx context.Context, client *http.Client, value, requestURL string) (*expectedChecksum, error) {
+	if !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+		return parseExpectedChecksum(value)
+	}
+
+	name, err := checksumFileName(requestURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchChecksumFile(ctx, client, value, name)
+}
+
+// fetchChecksumFile downloads the checksums file and returns the checksum of
+// the named file.
+func fetchChecksumFile(ctx context.Context, client *http.Client, checksumURL, name string) (*expectedChecksum, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, checksumURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("checksums file request responded with %q", response.Status)
+	}
+
+	data, _, err := readResponseBody(response, maxChecksumFileBytes, false)
+	if err != nil {
+		return nil, fmt.Errorf("reading checksums file: %w", err)
+	}
+
+	return parseChecksumFile(data, name)
+}
+
+// checksumFileName returns the name of the file at the URL, which is looked
+// up in a checksums file.
+func checksumFileName(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return "", errors.New("the URL has no file name to look up in the checksums file")
+	}
+
+	return name, nil
+}
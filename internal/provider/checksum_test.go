@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestParseExpectedChecksum(t *testing.T) {
+	testCases := map[string]struct {
+		value       string
+		expected    expectedChecksum
+		expectError bool
+	}{
+		"md5": {
+			value:    "md5:5D41402ABC4B2A76B9719D911017C592",
+			expected: expectedChecksum{algorithm: "md5", digest: "5d41402abc4b2a76b9719d911017c592"},
+		},
+		"sha256": {
+			value:    "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			expected: expectedChecksum{algorithm: "sha256", digest: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		},
+		"no-algorithm": {
+			value:       "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			expectError: true,
+		},
+		"unsupported-algorithm": {
+			value:       "sha1:aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d",
+			expectError: true,
+		},
+		"not-hex": {
+			value:       "md5:5d41402abc4b2a76b9719d911017c59z",
+			expectError: true,
+		},
+		"wrong-length": {
+			value:       "sha256:5d41402abc4b2a76b9719d911017c592",
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parseExpectedChecksum(testCase.value)
+
+			if testCase.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got %#v", actual)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if *actual != testCase.expected {
+				t.Errorf("expected %#v, got %#v", testCase.expected, *actual)
+			}
+		})
+	}
+}
+
+func TestParseChecksumFile(t *testing.T) {
+	sha256Hello := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	md5Hello := "5d41402abc4b2a76b9719d911017c592"
+
+	testCases := map[string]struct {
+		data        string
+		name        string
+		expected    expectedChecksum
+		expectError bool
+	}{
+		"gnu": {
+			data: "0000000000000000000000000000000000000000000000000000000000000000  other.zip\n" +
+				sha256Hello + "  hello.txt\n",
+			name:     "hello.txt",
+			expected: expectedChecksum{algorithm: "sha256", digest: sha256Hello},
+		},
+		"gnu-binary": {
+			data:     md5Hello + " *hello.txt\n",
+			name:     "hello.txt",
+			expected: expectedChecksum{algorithm: "md5", digest: md5Hello},
+		},
+		"bsd": {
+			data:     "# comment\nSHA256 (hello.txt) = " + sha256Hello + "\n",
+			name:     "hello.txt",
+			expected: expectedChecksum{algorithm: "sha256", digest: sha256Hello},
+		},
+		"digest-only": {
+			data:     sha256Hello + "\n",
+			name:     "anything",
+			expected: expectedChecksum{algorithm: "sha256", digest: sha256Hello},
+		},
+		"not-found": {
+			data:        sha256Hello + "  hello.txt\n" + md5Hello + "  other.txt\n",
+			name:        "missing.txt",
+			expectError: true,
+		},
+		"unknown-length": {
+			data:        "abcdef  hello.txt\n",
+			name:        "hello.txt",
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parseChecksumFile([]byte(testCase.data), testCase.name)
+
+			if testCase.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got %#v", actual)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if *actual != testCase.expected {
+				t.Errorf("expected %#v, got %#v", testCase.expected, *actual)
+			}
+		})
+	}
+}
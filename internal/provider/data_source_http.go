@@ -1,17 +1,24 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
-	"crypto/x509"
+	"encoding/base64"
+	"errors"
 	"fmt"
-	"io"
 	"mime"
 	"net/http"
-	"net/url"
+	"net/http/httptrace"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -19,16 +26,22 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"golang.org/x/net/http/httpproxy"
 )
 
-var _ datasource.DataSource = (*httpDataSource)(nil)
+var (
+	_ datasource.DataSource              = (*httpDataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*httpDataSource)(nil)
+)
 
 func NewHttpDataSource() datasource.DataSource {
-	return &httpDataSource{}
+	return &httpDataSource{
+		providerData: &httpProviderData{},
+	}
 }
 
-type httpDataSource struct{}
+type httpDataSource struct {
+	providerData *httpProviderData
+}
 
 func (d *httpDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
 	// This data source name unconventionally is equal to the provider name,
@@ -38,6 +51,24 @@ func (d *httpDataSource) Metadata(_ context.Context, _ datasource.MetadataReques
 	resp.TypeName = "http"
 }
 
+func (d *httpDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*httpProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *httpProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.providerData = providerData
+}
+
 func (d *httpDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: `
@@ -46,8 +77,10 @@ information about the response.
 
 The given URL may be either an ` + "`http`" + ` or ` + "`https`" + ` URL. At present this resource
 can only retrieve data from URLs that respond with ` + "`text/*`" + ` or
-` + "`application/json`" + ` content types, and expects the result to be UTF-8 encoded
-regardless of the returned content type header.
+` + "`application/json`" + ` content types into ` + "`response_body`" + `, decoded from the
+charset declared by the returned content type header, or UTF-8 if none is declared. Responses with
+other content types, such as binary data, can be retrieved from
+` + "`response_body_base64`" + ` instead.
 
 ~> **Important** Although ` + "`https`" + ` URLs can be used, there is currently no
 mechanism to authenticate the remote server except for general verification of
@@ -56,45 +89,160 @@ your control should be treated as untrustworthy.`,
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
-				Description: "The URL used for the request.",
+				Description: "The URL used for the request, including `query_params`.",
 				Computed:    true,
 			},
 
 			"url": schema.StringAttribute{
-				Description: "The URL for the request. Supported schemes are `http` and `https`.",
-				Required:    true,
+				Description: "The URL for the request. Supported schemes are `http` and `https`. " +
+					"A relative URL is resolved against the provider `base_url`.",
+				Required: true,
+			},
+
+			"query_params": schema.MapAttribute{
+				Description: "A map of query parameter names and lists of values, which are URL encoded and " +
+					"added to the query string of `url`. A parameter with several values is repeated, " +
+					"once for each value.",
+				ElementType: types.ListType{ElemType: types.StringType},
+				Optional:    true,
+			},
+
+			"request_url": schema.StringAttribute{
+				Description: "The fully encoded URL of the request, with `url` resolved against the provider " +
+					"`base_url` and `query_params` added to its query string.",
+				Computed: true,
 			},
 
 			"method": schema.StringAttribute{
 				Description: "The HTTP Method for the request. " +
-					"Allowed methods are a subset of methods defined in [RFC7231](https://datatracker.ietf.org/doc/html/rfc7231#section-4.3) namely, " +
-					"`GET`, `HEAD`, and `POST`. `POST` support is only intended for read-only URLs, such as submitting a search.",
+					"Allowed methods are `GET`, `HEAD`, `OPTIONS`, `POST` and `QUERY`, as defined in " +
+					"[RFC9110](https://datatracker.ietf.org/doc/html/rfc9110#section-9.3) and " +
+					"[draft-ietf-httpbis-safe-method-w-body](https://datatracker.ietf.org/doc/draft-ietf-httpbis-safe-method-w-body/). " +
+					"`POST` support is only intended for read-only URLs, such as submitting a search, and results " +
+					"in a warning as it is not a safe method. Defaults to `GET`.",
 				Optional: true,
 				Validators: []validator.String{
-					stringvalidator.OneOf([]string{
-						http.MethodGet,
-						http.MethodPost,
-						http.MethodHead,
-					}...),
+					stringvalidator.OneOf(dataSourceMethods...),
 				},
 			},
 
 			"request_headers": schema.MapAttribute{
-				Description: "A map of request header field names and values.",
+				Description: "A map of request header field names and values. " +
+					"These are merged with the provider `request_headers`, replacing any header of the same name.",
 				ElementType: types.StringType,
 				Optional:    true,
 			},
 
+			"sensitive_request_headers": schema.MapAttribute{
+				Description: "A map of request header field names and values, such as API keys, which are not " +
+					"shown in the plan output. These are merged with `request_headers`, replacing any header " +
+					"of the same name.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+			},
+
 			"request_body": schema.StringAttribute{
 				Description: "The request body as a string.",
 				Optional:    true,
 			},
 
+			"sensitive_request_body": schema.StringAttribute{
+				Description: "The request body as a string, which is not shown in the plan output. " +
+					"Conflicts with `request_body`.",
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("request_body")),
+				},
+			},
+
+			"request_form": schema.MapAttribute{
+				Description: "A map of form field names and values, sent as an `application/x-www-form-urlencoded` " +
+					"request body. The `Content-Type` header defaults to `application/x-www-form-urlencoded`. " +
+					"Conflicts with `request_body` and `sensitive_request_body`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Map{
+					mapvalidator.ConflictsWith(
+						path.MatchRoot("request_body"),
+						path.MatchRoot("sensitive_request_body"),
+					),
+				},
+			},
+
+			"response_body_sensitive": schema.BoolAttribute{
+				Description: "Whether the response body contains secrets. If `true`, the response body is " +
+					"exported in `sensitive_response_body` instead of `response_body`, `response_body_base64`, " +
+					"`response_json` and `body`, which are null along with the `response_body_md5`, " +
+					"`response_body_sha256` and `response_body_sha512` digests, and it is left out of error messages. " +
+					"Defaults to `false`.",
+				Optional: true,
+			},
+
+			"response_headers_sensitive": schema.SetAttribute{
+				Description: "The names of response headers which contain secrets, such as `Set-Cookie`. " +
+					"These headers are exported in `sensitive_response_headers` instead of `response_headers`, " +
+					"and are left out of error messages.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+
+			"expected_status_codes": schema.ListAttribute{
+				Description: "The response status codes which are considered successful, either as exact codes " +
+					"such as `200` or as classes such as `2xx`. Any other status code results in an error. " +
+					"Defaults to accepting any status code.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.RegexMatches(
+						statusCodePattern,
+						"must be a status code such as 200, or a class of status codes such as 2xx",
+					)),
+				},
+			},
+
+			"response_body_charset": schema.StringAttribute{
+				Description: "The charset used to decode the response body into `response_body`, such as " +
+					"`iso-8859-1` or `shift_jis`. This overrides the `charset` parameter of the response " +
+					"`Content-Type` header, for servers which declare the wrong charset. " +
+					"Defaults to the declared charset, or UTF-8 if there is none.",
+				Optional: true,
+			},
+
 			"response_body": schema.StringAttribute{
 				Description: "The response body returned as a string.",
 				Computed:    true,
 			},
 
+			"response_body_base64": schema.StringAttribute{
+				Description: "The response body returned as a base64 encoded string. " +
+					"Unlike `response_body`, this holds the raw bytes of the response unchanged, " +
+					"so it can be used for binary content such as images or archives.",
+				Computed: true,
+			},
+
+			"response_json": dynamicAttribute{schema.StringAttribute{
+				Description: "The response body decoded as JSON, in the same way as the `jsondecode` function. " +
+					"This is only set when the `Content-Type` of the response is `application/json` or " +
+					"ends with `+json`, and is null otherwise.",
+				Computed: true,
+			}},
+
+			"sensitive_response_body": schema.StringAttribute{
+				Description: "The response body returned as a string, when `response_body_sensitive` is `true`.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+
+			"sensitive_response_headers": schema.MapAttribute{
+				Description: "A map of the response headers listed in `response_headers_sensitive`, " +
+					"with duplicate headers concatenated as in `response_headers`.",
+				ElementType: types.StringType,
+				Computed:    true,
+				Sensitive:   true,
+			},
+
 			"body": schema.StringAttribute{
 				Description: "The response body returned as a string. " +
 					"**NOTE**: This is deprecated, use `response_body` instead.",
@@ -104,7 +252,8 @@ your control should be treated as untrustworthy.`,
 
 			"ca_cert_pem": schema.StringAttribute{
 				Description: "Certificate data of the Certificate Authority (CA) " +
-					"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.",
+					"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"Takes precedence over the provider `insecure`.",
 				Optional: true,
 				Validators: []validator.String{
 					stringvalidator.ConflictsWith(path.MatchRoot("insecure")),
@@ -116,6 +265,274 @@ your control should be treated as untrustworthy.`,
 				Optional:    true,
 			},
 
+			"client_cert_pem": schema.StringAttribute{
+				Description: "Client certificate used for mutual TLS authentication, " +
+					"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"Any intermediate certificates follow the client certificate. Requires `client_key_pem`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_key_pem")),
+					stringvalidator.ConflictsWith(path.MatchRoot("client_pkcs12_base64")),
+				},
+			},
+
+			"client_key_pem": schema.StringAttribute{
+				Description: "Private key of the client certificate " +
+					"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"PKCS#1, SEC 1 and PKCS#8 keys are supported, as are encrypted PKCS#8 keys " +
+					"when `client_key_password` is set. Requires `client_cert_pem`.",
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_cert_pem")),
+					stringvalidator.ConflictsWith(path.MatchRoot("client_pkcs12_base64")),
+				},
+			},
+
+			"client_pkcs12_base64": schema.StringAttribute{
+				Description: "Client certificate and private key used for mutual TLS authentication, " +
+					"as a base64 encoded PKCS#12 bundle, such as returned by `filebase64`. " +
+					"Bundles encrypted with AES and PBKDF2, as created by OpenSSL 3, and with the " +
+					"legacy 3DES and RC2 algorithms are supported.",
+				Optional:  true,
+				Sensitive: true,
+			},
+
+			"client_key_password": schema.StringAttribute{
+				Description: "Password used to decrypt an encrypted `client_key_pem` or the `client_pkcs12_base64` bundle.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+
+			"tls_min_version": schema.StringAttribute{
+				Description: "The minimum TLS version accepted, one of `1.0`, `1.1`, `1.2` and `1.3`. " +
+					"Defaults to the minimum version of the Go release the provider is built with, which is `1.2`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("1.0", "1.1", "1.2", "1.3"),
+				},
+			},
+
+			"tls_max_version": schema.StringAttribute{
+				Description: "The maximum TLS version accepted, one of `1.0`, `1.1`, `1.2` and `1.3`. " +
+					"Defaults to `1.3`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("1.0", "1.1", "1.2", "1.3"),
+				},
+			},
+
+			"tls_cipher_suites": schema.ListAttribute{
+				Description: "The cipher suites accepted for TLS 1.0 to 1.2, by their IANA names such as " +
+					"`TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256`. TLS 1.3 cipher suites are not configurable. " +
+					"Defaults to the secure cipher suites of the Go release the provider is built with.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+
+			"tls_server_name": schema.StringAttribute{
+				Description: "The server name sent in the TLS Server Name Indication (SNI) extension and " +
+					"verified against the server certificate, instead of the host of `url`.",
+				Optional: true,
+			},
+
+			"tls_alpn_protocols": schema.ListAttribute{
+				Description: "The protocols offered with Application-Layer Protocol Negotiation (ALPN), in order " +
+					"of preference, among `h2` and `http/1.1`. Set to `[\"http/1.1\"]` to disable HTTP/2. " +
+					"Defaults to `[\"h2\", \"http/1.1\"]`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.UniqueValues(),
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(alpnHTTP2, alpnHTTP1)),
+				},
+			},
+
+			"request_timeout_ms": schema.Int64Attribute{
+				Description: "The request timeout in milliseconds, covering the whole exchange " +
+					"including reading the response body. Overrides the provider `request_timeout_ms`. " +
+					"Defaults to no timeout.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"connect_timeout_ms": schema.Int64Attribute{
+				Description: "The timeout in milliseconds for establishing the connection to the server, " +
+					"including resolving its host name. Defaults to `30000`.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"tls_handshake_timeout_ms": schema.Int64Attribute{
+				Description: "The timeout in milliseconds for completing the TLS handshake. Defaults to `10000`.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"response_header_timeout_ms": schema.Int64Attribute{
+				Description: "The timeout in milliseconds for receiving the response headers, " +
+					"once the request has been sent. Defaults to no timeout.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"resolve": schema.MapAttribute{
+				Description: "A map of `host:port` addresses to the IP address connected to instead of " +
+					"resolving the host name, in the same way as the `curl --resolve` option, such as " +
+					"`{ \"example.com:443\" = \"192.0.2.10\" }`. The request keeps the host name in its " +
+					"`Host` header and TLS server name. Not used for requests sent through an HTTP proxy.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+
+			"dns_servers": schema.ListAttribute{
+				Description: "The DNS servers used to resolve host names instead of those of the system, " +
+					"as IP addresses with an optional port, such as `192.0.2.53` or `[2001:db8::53]:5353`. " +
+					"The servers are queried in turn. Not used for requests sent through an HTTP proxy.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+
+			"unix_socket_path": schema.StringAttribute{
+				Description: "The path of a Unix domain socket, such as `/var/run/docker.sock`, over which " +
+					"requests are sent instead of connecting to the host of `url`, which only supplies the " +
+					"path and `Host` header. Redirects are also followed over the socket. Conflicts with " +
+					"`proxy`, `resolve` and `dns_servers`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("proxy"),
+						path.MatchRoot("resolve"),
+						path.MatchRoot("dns_servers"),
+					),
+				},
+			},
+
+			"remote_ip": schema.StringAttribute{
+				Description: "The IP address connected to for the final response, which is that of the proxy " +
+					"when the request is sent through a proxy, or the one the response was received from when it " +
+					"is served from the cache without a request. Null when `unix_socket_path` is set.",
+				Computed: true,
+			},
+
+			"max_response_bytes": schema.Int64Attribute{
+				Description: "The maximum size of the response body in bytes, which protects Terraform from " +
+					"running out of memory when a URL returns a large file. A larger response body results in " +
+					"an error, unless `truncate_response` is `true`. Defaults to `104857600` (100 MiB).",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+
+			"truncate_response": schema.BoolAttribute{
+				Description: "Whether a response body larger than `max_response_bytes` is truncated, with a warning, " +
+					"instead of resulting in an error. Defaults to `false`.",
+				Optional: true,
+			},
+
+			"response_truncated": schema.BoolAttribute{
+				Description: "Whether the response body was truncated to `max_response_bytes`. " +
+					"`response_json` is null when the response body was truncated.",
+				Computed: true,
+			},
+
+			"expected_checksum": schema.StringAttribute{
+				Description: "The checksum the response body must match, otherwise the data source fails. " +
+					"Either an algorithm and hex encoded digest, such as `sha256:2c26b46b...`, where the " +
+					"algorithm is one of `md5`, `sha256` and `sha512`, or the `http` or `https` URL of a " +
+					"checksums file in the format written by `sha256sum`, in which the digest of the file " +
+					"named by the last element of the request URL path is looked up.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(expectedChecksumPattern,
+						"must be an algorithm and hex encoded digest, such as sha256:2c26b46b..., "+
+							"or the URL of a checksums file"),
+				},
+			},
+
+			"response_body_md5": schema.StringAttribute{
+				Description: "The hex encoded MD5 digest of the response body, before any charset decoding. " +
+					"Null when `response_body_sensitive` is `true`.",
+				Computed: true,
+			},
+
+			"response_body_sha256": schema.StringAttribute{
+				Description: "The hex encoded SHA-256 digest of the response body, before any charset decoding. " +
+					"Null when `response_body_sensitive` is `true`.",
+				Computed: true,
+			},
+
+			"response_body_sha512": schema.StringAttribute{
+				Description: "The hex encoded SHA-512 digest of the response body, before any charset decoding. " +
+					"Null when `response_body_sensitive` is `true`.",
+				Computed: true,
+			},
+
+			"cache": schema.BoolAttribute{
+				Description: "Whether the response is cached on disk, in the provider `cache_dir`. A cached " +
+					"response is revalidated with a conditional request using its `ETag` and `Last-Modified` " +
+					"headers, and used again if the server responds with `304 Not Modified`. Responses are " +
+					"cached by method, URL, request headers and request body, and by the `auth`, `ca_cert_pem`, " +
+					"`insecure`, client certificate, `tls_*`, `resolve`, `dns_servers`, `unix_socket_path`, " +
+					"`proxy` and redirect settings. The cache is not used when `response_body_sensitive` is `true` or " +
+					"`response_headers_sensitive` is set. Defaults to `false`.",
+				Optional: true,
+			},
+
+			"from_cache": schema.BoolAttribute{
+				Description: "Whether the response was served from the cache, either because the server " +
+					"responded with `304 Not Modified` or because it is younger than the provider `cache_ttl_ms`.",
+				Computed: true,
+			},
+
+			"follow_redirects": schema.BoolAttribute{
+				Description: "Whether redirect responses are followed. When `false`, the redirect response " +
+					"itself is returned. Defaults to `true`.",
+				Optional: true,
+			},
+
+			"max_redirects": schema.Int64Attribute{
+				Description: "The maximum number of redirects followed before the request fails. Defaults to `10`.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+
+			"allow_cross_host_redirects": schema.BoolAttribute{
+				Description: "Whether redirects to a different host are followed. Defaults to `true`.",
+				Optional:    true,
+			},
+
+			"allow_insecure_redirects": schema.BoolAttribute{
+				Description: "Whether redirects from an `https` URL to an `http` URL are followed. Defaults to `true`.",
+				Optional:    true,
+			},
+
+			"final_url": schema.StringAttribute{
+				Description: "The URL of the final request, after following any redirects.",
+				Computed:    true,
+			},
+
+			"redirect_chain": schema.ListAttribute{
+				Description: "The URLs requested, in order, starting with the request URL and ending with `final_url`.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+
 			"response_headers": schema.MapAttribute{
 				Description: `A map of response header field names and values.` +
 					` Duplicate headers are concatenated according to [RFC2616](https://www.w3.org/Protocols/rfc2616/rfc2616-sec4.html#sec4.2).`,
@@ -123,10 +540,182 @@ your control should be treated as untrustworthy.`,
 				Computed:    true,
 			},
 
+			"allowed_methods": schema.ListAttribute{
+				Description: "The methods listed in the `Allow` response header, such as returned for an " +
+					"`OPTIONS` request or a `405 Method Not Allowed` response. Null if there is no `Allow` header.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+
 			"status_code": schema.Int64Attribute{
 				Description: `The HTTP response status code.`,
 				Computed:    true,
 			},
+
+			"request_attempts": schema.Int64Attribute{
+				Description: "The number of attempts made to complete the request, including the first one. " +
+					"A value greater than one indicates that retries were needed.",
+				Computed: true,
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"auth": authBlock(),
+
+			"proxy": schema.SingleNestedBlock{
+				Description: "Proxy used for the request, instead of the provider `proxy`, `proxy_pac_url` " +
+					"and `proxy_pac_content` or the `HTTP_PROXY`, `HTTPS_PROXY` and `NO_PROXY` environment " +
+					"variables. HTTP and HTTPS proxies are supported, " +
+					"using `CONNECT` for `https` URLs, as are SOCKS5 proxies. When `url` is not set, the request " +
+					"is sent directly.",
+				Attributes: map[string]schema.Attribute{
+					"url": schema.StringAttribute{
+						Description: "The URL of the proxy, with one of the `http`, `https`, `socks5` and `socks5h` " +
+							"schemes. The host name of the request is resolved locally with `socks5` and by the " +
+							"proxy with `socks5h`.",
+						Optional: true,
+					},
+					"username": schema.StringAttribute{
+						Description: "The username used to authenticate with the proxy.",
+						Optional:    true,
+						Validators: []validator.String{
+							stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("url")),
+						},
+					},
+					"password": schema.StringAttribute{
+						Description: "The password used to authenticate with the proxy.",
+						Optional:    true,
+						Sensitive:   true,
+						Validators: []validator.String{
+							stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("username")),
+						},
+					},
+					"no_proxy": schema.ListAttribute{
+						Description: "Host names, domain suffixes, IP addresses or CIDR ranges which are " +
+							"requested directly, using the same syntax as the `NO_PROXY` environment variable.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+			},
+
+			"request_multipart": schema.SingleNestedBlock{
+				Description: "Send a `multipart/form-data` request body made of form fields and files. " +
+					"The `Content-Type` header is set to `multipart/form-data` with a generated boundary. " +
+					"Conflicts with `request_body`, `sensitive_request_body` and `request_form`.",
+				Attributes: map[string]schema.Attribute{
+					"fields": schema.MapAttribute{
+						Description: "A map of form field names and values, sent before the files.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+				Blocks: map[string]schema.Block{
+					"file": schema.ListNestedBlock{
+						Description: "A file, whose content is given by exactly one of `content`, " +
+							"`content_base64` or `source`.",
+						NestedObject: schema.NestedBlockObject{
+							Attributes: map[string]schema.Attribute{
+								"name": schema.StringAttribute{
+									Description: "The form field name of the file.",
+									Required:    true,
+								},
+								"filename": schema.StringAttribute{
+									Description: "The file name sent to the server. Defaults to the base name " +
+										"of `source`, or to `name`.",
+									Optional: true,
+								},
+								"content_type": schema.StringAttribute{
+									Description: "The content type of the file. Defaults to `application/octet-stream`.",
+									Optional:    true,
+								},
+								"content": schema.StringAttribute{
+									Description: "The content of the file as a string.",
+									Optional:    true,
+									Validators: []validator.String{
+										stringvalidator.ExactlyOneOf(
+											path.MatchRelative().AtParent().AtName("content_base64"),
+											path.MatchRelative().AtParent().AtName("source"),
+										),
+									},
+								},
+								"content_base64": schema.StringAttribute{
+									Description: "The content of the file as a base64 encoded string, " +
+										"for binary content.",
+									Optional: true,
+								},
+								"source": schema.StringAttribute{
+									Description: "The path of a local file whose content is sent.",
+									Optional:    true,
+								},
+							},
+						},
+					},
+				},
+				Validators: []validator.Object{
+					objectvalidator.ConflictsWith(
+						path.MatchRoot("request_body"),
+						path.MatchRoot("sensitive_request_body"),
+						path.MatchRoot("request_form"),
+					),
+				},
+			},
+
+			"retry": schema.SingleNestedBlock{
+				Description: "Retry the request when it fails with a retryable status code or transport error. " +
+					"The delay between attempts grows exponentially from `min_delay_ms` to `max_delay_ms`, with jitter. " +
+					"A `Retry-After` response header takes precedence over the computed delay, up to `max_delay_ms`.",
+				Attributes: map[string]schema.Attribute{
+					"attempts": schema.Int64Attribute{
+						Description: "The number of times the request is to be retried. For example, if 2 is specified, " +
+							"the request will be tried a maximum of 3 times. Defaults to `0`.",
+						Optional: true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"min_delay_ms": schema.Int64Attribute{
+						Description: "The minimum delay between retry requests in milliseconds. Defaults to `1000`.",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"max_delay_ms": schema.Int64Attribute{
+						Description: "The maximum delay between retry requests in milliseconds. Defaults to `30000`.",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+							int64validator.AtLeastSumOf(path.MatchRelative().AtParent().AtName("min_delay_ms")),
+						},
+					},
+					"status_codes": schema.SetAttribute{
+						Description: "The response status codes which are retried. " +
+							"Defaults to `429`, `500`, `502`, `503` and `504`.",
+						ElementType: types.Int64Type,
+						Optional:    true,
+						Validators: []validator.Set{
+							setvalidator.ValueInt64sAre(int64validator.Between(100, 599)),
+						},
+					},
+					"transport_errors": schema.SetAttribute{
+						Description: "The classes of transport error which are retried, any of " +
+							"`connection_refused`, `connection_reset`, `dns`, `eof` and `timeout`. " +
+							"Defaults to all of them except `dns`.",
+						ElementType: types.StringType,
+						Optional:    true,
+						Validators: []validator.Set{
+							setvalidator.ValueStringsAre(stringvalidator.OneOf(
+								transportErrorConnectionRefused,
+								transportErrorConnectionReset,
+								transportErrorDNS,
+								transportErrorEOF,
+								transportErrorTimeout,
+							)),
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -139,67 +728,181 @@ func (d *httpDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	requestURL := model.URL.ValueString()
+	requestURL, err := d.providerData.resolveURL(model.URL.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("url"),
+			"Error resolving URL",
+			fmt.Sprintf("Error resolving URL against the provider base_url: %s", err),
+		)
+		return
+	}
+
+	requestURL, diags = appendQueryParams(ctx, requestURL, model.QueryParams)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	method := model.Method.ValueString()
 	requestHeaders := model.RequestHeaders
-	requestBody := strings.NewReader(model.RequestBody.ValueString())
+
+	requestBody, requestContentType, diags := newRequestBody(ctx, model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	if method == "" {
 		method = "GET"
 	}
 
-	caCertificate := model.CaCertificate
-
-	tr, ok := http.DefaultTransport.(*http.Transport)
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Error configuring http transport",
-			"Error http: Can't configure http transport.",
+	if !isMethodSafe(method) {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("method"),
+			fmt.Sprintf("Request method %s is not safe", method),
+			"Requests with this method may change the state of the server. The data source is read whenever "+
+				"Terraform plans or applies, so the request may be sent many times. Use the http_request resource "+
+				"for requests which have side effects.",
 		)
-		return
 	}
 
-	// Prevent issues with multiple data source configurations modifying the shared transport.
-	clonedTr := tr.Clone()
-
-	// Prevent issues with tests caching the proxy configuration.
-	clonedTr.Proxy = func(req *http.Request) (*url.URL, error) {
-		return httpproxy.FromEnvironment().ProxyFunc()(req.URL)
+	clonedTr, diags := d.providerData.newTransport(model.CaCertificate, model.Insecure)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if clonedTr.TLSClientConfig == nil {
-		clonedTr.TLSClientConfig = &tls.Config{}
+	connectTimeout := defaultConnectTimeout
+	if !model.ConnectTimeout.IsNull() {
+		connectTimeout = time.Duration(model.ConnectTimeout.ValueInt64()) * time.Millisecond
 	}
 
-	if !model.Insecure.IsNull() {
-		if clonedTr.TLSClientConfig == nil {
-			clonedTr.TLSClientConfig = &tls.Config{}
+	proxy := d.providerData.proxy
+	if model.Proxy != nil {
+		proxy, err = newProxyConfig(ctx, model.Proxy)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("proxy").AtName("url"),
+				"Invalid proxy configuration",
+				fmt.Sprintf("Invalid proxy configuration: %s", err),
+			)
+			return
 		}
-		clonedTr.TLSClientConfig.InsecureSkipVerify = model.Insecure.ValueBool()
 	}
 
-	// Use `ca_cert_pem` cert pool
-	if !caCertificate.IsNull() {
-		caCertPool := x509.NewCertPool()
-		if ok := caCertPool.AppendCertsFromPEM([]byte(caCertificate.ValueString())); !ok {
-			resp.Diagnostics.AddError(
-				"Error configuring TLS client",
-				"Error tls: Can't add the CA certificate to certificate pool. Only PEM encoded certificates are supported.",
-			)
+	var resolveOverrides map[string]string
+	if !model.Resolve.IsNull() {
+		diags = model.Resolve.ElementsAs(ctx, &resolveOverrides, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
 			return
 		}
+	}
+
+	resolveOverrides, err = parseResolveOverrides(resolveOverrides)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("resolve"),
+			"Invalid resolve address",
+			fmt.Sprintf("Invalid resolve address: %s", err),
+		)
+		return
+	}
 
-		if clonedTr.TLSClientConfig == nil {
-			clonedTr.TLSClientConfig = &tls.Config{}
+	var dnsServers []string
+	if !model.DNSServers.IsNull() {
+		diags = model.DNSServers.ElementsAs(ctx, &dnsServers, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
 		}
-		clonedTr.TLSClientConfig.RootCAs = caCertPool
+	}
+
+	dnsServers, err = parseDNSServers(dnsServers)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("dns_servers"),
+			"Invalid DNS server",
+			fmt.Sprintf("Invalid DNS server: %s", err),
+		)
+		return
+	}
+
+	netDialer := newDialer(connectTimeout)
+	dialer := newResolvingDialer(netDialer, resolveOverrides, dnsServers)
+
+	if err := configureProxy(clonedTr, proxy, dialer); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("proxy"),
+			"Error configuring proxy",
+			fmt.Sprintf("Error configuring proxy: %s", err),
+		)
+		return
+	}
+
+	if !model.UnixSocketPath.IsNull() {
+		configureUnixSocket(clonedTr, netDialer, model.UnixSocketPath.ValueString())
+	}
+
+	if !model.TLSHandshakeTimeout.IsNull() {
+		clonedTr.TLSHandshakeTimeout = time.Duration(model.TLSHandshakeTimeout.ValueInt64()) * time.Millisecond
+	}
+
+	if !model.ResponseHeaderTimeout.IsNull() {
+		clonedTr.ResponseHeaderTimeout = time.Duration(model.ResponseHeaderTimeout.ValueInt64()) * time.Millisecond
+	}
+
+	clientCert, diags := newClientCertificate(model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if clientCert != nil {
+		clonedTr.TLSClientConfig.Certificates = []tls.Certificate{*clientCert}
+	}
+
+	resp.Diagnostics.Append(configureTLS(ctx, clonedTr, model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	retry, diags := newRetryPolicy(ctx, model.Retry)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	requestTimeout := d.providerData.requestTimeout
+	if !model.RequestTimeout.IsNull() {
+		requestTimeout = time.Duration(model.RequestTimeout.ValueInt64()) * time.Millisecond
+	}
+
+	redirect := &redirectPolicy{
+		follow:         model.FollowRedirects.IsNull() || model.FollowRedirects.ValueBool(),
+		maxRedirects:   defaultMaxRedirects,
+		allowCrossHost: model.AllowCrossHostRedirects.IsNull() || model.AllowCrossHostRedirects.ValueBool(),
+		allowInsecure:  model.AllowInsecureRedirects.IsNull() || model.AllowInsecureRedirects.ValueBool(),
+	}
+
+	if !model.MaxRedirects.IsNull() {
+		redirect.maxRedirects = int(model.MaxRedirects.ValueInt64())
 	}
 
 	client := &http.Client{
-		Transport: clonedTr,
+		Transport:     clonedTr,
+		Timeout:       requestTimeout,
+		CheckRedirect: redirect.checkRedirect,
 	}
 
-	request, err := http.NewRequestWithContext(ctx, method, requestURL, requestBody)
+	phase := newRequestPhase()
+	ctx = httptrace.WithClientTrace(ctx, phase.clientTrace())
+
+	remote := &remoteIP{}
+	ctx = httptrace.WithClientTrace(ctx, remote.clientTrace())
+
+	request, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(requestBody))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating request",
@@ -208,6 +911,16 @@ func (d *httpDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
+	// The form content type can be replaced by the request headers, unlike the
+	// multipart one which holds the boundary.
+	if requestContentType == contentTypeForm {
+		request.Header.Set("Content-Type", requestContentType)
+	}
+
+	for name, value := range d.providerData.requestHeaders {
+		request.Header.Set(name, value)
+	}
+
 	for name, value := range requestHeaders.Elements() {
 		var header string
 		diags = tfsdk.ValueAs(ctx, value, &header)
@@ -219,26 +932,216 @@ func (d *httpDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		request.Header.Set(name, header)
 	}
 
-	response, err := client.Do(request)
+	if !model.SensitiveRequestHeaders.IsNull() {
+		var sensitiveHeaders map[string]string
+		diags = model.SensitiveRequestHeaders.ElementsAs(ctx, &sensitiveHeaders, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for name, value := range sensitiveHeaders {
+			request.Header.Set(name, value)
+		}
+	}
+
+	if model.RequestMultipart != nil {
+		request.Header.Set("Content-Type", requestContentType)
+	}
+
+	sensitiveBody := model.ResponseBodySensitive.ValueBool()
+	sensitiveHeaders := map[string]bool{}
+	if !model.ResponseHeadersSensitive.IsNull() {
+		var names []string
+		diags = model.ResponseHeadersSensitive.ElementsAs(ctx, &names, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for _, name := range names {
+			sensitiveHeaders[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+
+	// The OAuth 2.0 token and the checksums file are requested from other
+	// servers than that of url, so they are sent with the proxy, certificate
+	// verification and client certificate of the data source, but without the
+	// unix_socket_path, resolve, dns_servers, tls_server_name, ALPN and cipher
+	// suite settings, which only apply to the server of url.
+	otherTr, diags := d.providerData.newTransport(model.CaCertificate, model.Insecure)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := configureProxy(otherTr, proxy, &resolvingDialer{dialer: netDialer}); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("proxy"),
+			"Error configuring proxy",
+			fmt.Sprintf("Error configuring proxy: %s", err),
+		)
+		return
+	}
+
+	if clientCert != nil {
+		otherTr.TLSClientConfig.Certificates = []tls.Certificate{*clientCert}
+	}
+
+	otherClient := &http.Client{
+		Transport: otherTr,
+		Timeout:   requestTimeout,
+	}
+
+	client.Transport, err = newAuthTransport(ctx, clonedTr, otherTr, request.URL, model.Auth, d.providerData.oauth2Tokens)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error making request",
-			fmt.Sprintf("Error making request: %s", err),
+		resp.Diagnostics.AddAttributeError(
+			path.Root("auth"),
+			"Invalid authentication configuration",
+			fmt.Sprintf("Invalid authentication configuration: %s", err),
 		)
 		return
 	}
 
+	var cache *responseCache
+	var cacheKey string
+	var cached *cacheEntry
+	if model.Cache.ValueBool() {
+		switch {
+		case sensitiveBody:
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("cache"),
+				"Response cache not used",
+				"The response is not cached because response_body_sensitive is true, so that it is not stored on disk.",
+			)
+		case len(sensitiveHeaders) > 0:
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("cache"),
+				"Response cache not used",
+				"The response is not cached because response_headers_sensitive is set, so that the sensitive "+
+					"response headers are not stored on disk.",
+			)
+		default:
+			settings, diags := cacheSettings(ctx, req.Config)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			cache = d.providerData.responseCache
+			cacheKey = cache.key(request, requestBody, settings)
+			cached, err = cache.load(cacheKey)
+			if err != nil {
+				resp.Diagnostics.AddAttributeWarning(
+					path.Root("cache"),
+					"Error reading response cache",
+					fmt.Sprintf("The response cache is not used: %s", err),
+				)
+				cache, cached = nil, nil
+			}
+		}
+	}
+
+	var response *http.Response
+	var attempts int
+	fromCache := cached != nil && cache.fresh(cached)
+	if fromCache {
+		response = cached.response(cached.request(request))
+	} else {
+		if cached != nil {
+			cached.setConditionalHeaders(request)
+		}
+
+		response, attempts, err = retry.do(ctx, client, request)
+		if err != nil {
+			summary, detail := "Error making request", fmt.Sprintf("Error making request: %s", err)
+			if isTimeoutError(err) {
+				summary, detail = "Request timeout", fmt.Sprintf("The request timed out while %s: %s", phase, err)
+			}
+
+			if attempts > 1 {
+				detail += fmt.Sprintf(" (giving up after %d attempts)", attempts)
+			}
+
+			resp.Diagnostics.AddError(summary, detail)
+			return
+		}
+
+		if cached != nil && response.StatusCode == http.StatusNotModified {
+			response.Body.Close()
+
+			cached.revalidate(response, remote.String())
+			if err := cache.store(cacheKey, cached); err != nil {
+				resp.Diagnostics.AddAttributeWarning(
+					path.Root("cache"),
+					"Error writing response cache",
+					fmt.Sprintf("Error writing response cache: %s", err),
+				)
+			}
+
+			response, fromCache = cached.response(response.Request), true
+		}
+	}
+
 	defer response.Body.Close()
 
+	// Any later request, such as that of a checksums file, must not replace the
+	// IP address of the response. A response used from the cache without a
+	// request has the IP address it was received from.
+	responseIP := remote.String()
+	if responseIP == "" && fromCache {
+		responseIP = cached.RemoteIP
+	}
+
+	remoteIPState := types.StringNull()
+	if responseIP != "" {
+		remoteIPState = types.StringValue(responseIP)
+	}
+
+	phase.set(requestPhaseResponseBody)
+
 	contentType := response.Header.Get("Content-Type")
 	if !isContentTypeText(contentType) {
 		resp.Diagnostics.AddWarning(
 			fmt.Sprintf("Content-Type is not recognized as a text type, got %q", contentType),
-			"If the content is binary data, Terraform may not properly handle the contents of the response.",
+			"If the content is binary data, Terraform may not properly handle the contents of the response. "+
+				"Use response_body_base64 to access the unmodified response body.",
 		)
 	}
 
-	bytes, err := io.ReadAll(response.Body)
+	maxResponseBytes := int64(defaultMaxResponseBytes)
+	if !model.MaxResponseBytes.IsNull() {
+		maxResponseBytes = model.MaxResponseBytes.ValueInt64()
+	}
+
+	body, truncated, err := readResponseBody(response, maxResponseBytes, model.TruncateResponse.ValueBool())
+	var tooLargeErr *responseTooLargeError
+	if errors.As(err, &tooLargeErr) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_response_bytes"),
+			"Response body too large",
+			fmt.Sprintf("The %s. Increase max_response_bytes to read it, "+
+				"or set truncate_response to read only the start of it.", err),
+		)
+		return
+	}
+
+	if truncated {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("max_response_bytes"),
+			"Response body truncated",
+			fmt.Sprintf("The response body is larger than the limit of %d bytes, and was truncated.", maxResponseBytes),
+		)
+	}
+
+	if err != nil && isTimeoutError(err) {
+		resp.Diagnostics.AddError(
+			"Request timeout",
+			fmt.Sprintf("The request timed out while %s: %s", phase, err),
+		)
+		return
+	}
+
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading response body",
@@ -247,12 +1150,104 @@ func (d *httpDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	responseBody := string(bytes)
+	if !model.ExpectedStatusCodes.IsNull() {
+		var expectedStatusCodes []string
+		diags = model.ExpectedStatusCodes.ElementsAs(ctx, &expectedStatusCodes, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if !statusCodeMatches(expectedStatusCodes, response.StatusCode) {
+			resp.Diagnostics.AddError(
+				"Unexpected response status code",
+				unexpectedStatusDetail(expectedStatusCodes, response, body, sensitiveHeaders, sensitiveBody),
+			)
+			return
+		}
+	}
+
+	if !model.ExpectedChecksum.IsNull() {
+		expected, err := newExpectedChecksum(ctx, otherClient, model.ExpectedChecksum.ValueString(), requestURL)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("expected_checksum"),
+				"Error reading expected checksum",
+				fmt.Sprintf("Error reading expected checksum: %s", err),
+			)
+			return
+		}
+
+		if actual := hexDigest(expected.algorithm, body); actual != expected.digest {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("expected_checksum"),
+				"Checksum mismatch",
+				fmt.Sprintf("The %s checksum of the response body is %s, expected %s.", expected.algorithm, actual, expected.digest),
+			)
+			return
+		}
+	}
+
+	if cache != nil && !fromCache && cache.isCacheable(response, truncated) {
+		if err := cache.store(cacheKey, newCacheEntry(response, body, responseIP)); err != nil {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("cache"),
+				"Error writing response cache",
+				fmt.Sprintf("Error writing response cache: %s", err),
+			)
+		}
+	}
+
+	charset := responseCharset(contentType)
+	if !model.ResponseBodyCharset.IsNull() {
+		charset = model.ResponseBodyCharset.ValueString()
+	}
+
+	decodedBytes, err := decodeCharset(body, charset)
+	switch {
+	case err != nil && !model.ResponseBodyCharset.IsNull():
+		resp.Diagnostics.AddAttributeError(
+			path.Root("response_body_charset"),
+			"Error decoding response body",
+			fmt.Sprintf("Error decoding response body: %s", err),
+		)
+		return
+	case err != nil:
+		resp.Diagnostics.AddWarning(
+			"Error decoding response body",
+			fmt.Sprintf("The charset of Content-Type %q is not supported, the response body is used without decoding. ", contentType)+
+				"Use response_body_charset to decode the response body with another charset.",
+		)
+		decodedBytes = body
+	}
+
+	// A sensitive body is not decoded, so that it never fails the read with an
+	// error describing its content.
+	responseJSON := dynamicNull()
+	if isResponseJSON(contentType) && len(decodedBytes) > 0 && !truncated && !sensitiveBody {
+		value, err := decodeJSON(decodedBytes)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("response_json"),
+				"Error decoding JSON response body",
+				fmt.Sprintf("The response has Content-Type %q, but its body is not valid JSON: %s", contentType, err),
+			)
+			return
+		}
+		responseJSON = dynamicValue{value: value}
+	}
+
+	responseBody := string(decodedBytes)
 
 	responseHeaders := make(map[string]string)
+	sensitiveResponseHeaders := make(map[string]string)
 	for k, v := range response.Header {
 		// Concatenate according to RFC2616
 		// cf. https://www.w3.org/Protocols/rfc2616/rfc2616-sec4.html#sec4.2
+		if sensitiveHeaders[k] {
+			sensitiveResponseHeaders[k] = strings.Join(v, ", ")
+			continue
+		}
 		responseHeaders[k] = strings.Join(v, ", ")
 	}
 
@@ -262,11 +1257,63 @@ func (d *httpDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
+	sensitiveRespHeadersState := types.MapNull(types.StringType)
+	if !model.ResponseHeadersSensitive.IsNull() {
+		sensitiveRespHeadersState, diags = types.MapValueFrom(ctx, types.StringType, sensitiveResponseHeaders)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	redirectChainState, diags := types.ListValueFrom(ctx, types.StringType, redirectChain(response))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	allowedMethodsState := types.ListNull(types.StringType)
+	if allowedMethods := parseAllowHeader(response.Header); allowedMethods != nil {
+		allowedMethodsState, diags = types.ListValueFrom(ctx, types.StringType, allowedMethods)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	model.ID = types.StringValue(requestURL)
+	model.RequestURL = types.StringValue(requestURL)
+	model.FinalURL = types.StringValue(response.Request.URL.String())
+	model.RedirectChain = redirectChainState
 	model.ResponseHeaders = respHeadersState
+	model.SensitiveResponseHeaders = sensitiveRespHeadersState
+	model.AllowedMethods = allowedMethodsState
 	model.ResponseBody = types.StringValue(responseBody)
+	model.ResponseBodyBase64 = types.StringValue(base64.StdEncoding.EncodeToString(body))
+	model.ResponseJSON = responseJSON
+	model.SensitiveResponseBody = types.StringNull()
 	model.Body = types.StringValue(responseBody)
+	model.ResponseBodyMD5 = types.StringValue(hexDigest("md5", body))
+	model.ResponseBodySHA256 = types.StringValue(hexDigest("sha256", body))
+	model.ResponseBodySHA512 = types.StringValue(hexDigest("sha512", body))
+
+	// The digests of a short secret can be reversed by brute force, so they
+	// are left out along with the body.
+	if sensitiveBody {
+		model.ResponseBody = types.StringNull()
+		model.ResponseBodyBase64 = types.StringNull()
+		model.ResponseJSON = dynamicNull()
+		model.SensitiveResponseBody = types.StringValue(responseBody)
+		model.Body = types.StringNull()
+		model.ResponseBodyMD5 = types.StringNull()
+		model.ResponseBodySHA256 = types.StringNull()
+		model.ResponseBodySHA512 = types.StringNull()
+	}
+	model.ResponseTruncated = types.BoolValue(truncated)
+	model.FromCache = types.BoolValue(fromCache)
 	model.StatusCode = types.Int64Value(int64(response.StatusCode))
+	model.RequestAttempts = types.Int64Value(int64(attempts))
+	model.RemoteIP = remoteIPState
 
 	diags = resp.State.Set(ctx, model)
 	resp.Diagnostics.Append(diags...)
@@ -277,21 +1324,23 @@ func (d *httpDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 // See https://github.com/hashicorp/terraform/pull/3858#issuecomment-156856738
 func isContentTypeText(contentType string) bool {
 
-	parsedType, params, err := mime.ParseMediaType(contentType)
+	parsedType, _, err := mime.ParseMediaType(contentType)
 	if err != nil {
 		return false
 	}
 
 	allowedContentTypes := []*regexp.Regexp{
 		regexp.MustCompile("^text/.+"),
-		regexp.MustCompile("^application/json$"),
 		regexp.MustCompile(`^application/samlmetadata\+xml`),
 	}
 
+	if isContentTypeJSON(parsedType) {
+		return true
+	}
+
 	for _, r := range allowedContentTypes {
 		if r.MatchString(parsedType) {
-			charset := strings.ToLower(params["charset"])
-			return charset == "" || charset == "utf-8" || charset == "us-ascii"
+			return true
 		}
 	}
 
@@ -299,15 +1348,74 @@ func isContentTypeText(contentType string) bool {
 }
 
 type modelV0 struct {
-	ID              types.String `tfsdk:"id"`
-	URL             types.String `tfsdk:"url"`
-	Method          types.String `tfsdk:"method"`
-	RequestHeaders  types.Map    `tfsdk:"request_headers"`
-	RequestBody     types.String `tfsdk:"request_body"`
-	ResponseHeaders types.Map    `tfsdk:"response_headers"`
-	CaCertificate   types.String `tfsdk:"ca_cert_pem"`
-	Insecure        types.Bool   `tfsdk:"insecure"`
-	ResponseBody    types.String `tfsdk:"response_body"`
-	Body            types.String `tfsdk:"body"`
-	StatusCode      types.Int64  `tfsdk:"status_code"`
+	ID                       types.String    `tfsdk:"id"`
+	URL                      types.String    `tfsdk:"url"`
+	QueryParams              types.Map       `tfsdk:"query_params"`
+	RequestURL               types.String    `tfsdk:"request_url"`
+	Method                   types.String    `tfsdk:"method"`
+	RequestHeaders           types.Map       `tfsdk:"request_headers"`
+	SensitiveRequestHeaders  types.Map       `tfsdk:"sensitive_request_headers"`
+	RequestBody              types.String    `tfsdk:"request_body"`
+	SensitiveRequestBody     types.String    `tfsdk:"sensitive_request_body"`
+	RequestForm              types.Map       `tfsdk:"request_form"`
+	RequestMultipart         *multipartModel `tfsdk:"request_multipart"`
+	ResponseBodySensitive    types.Bool      `tfsdk:"response_body_sensitive"`
+	ResponseHeadersSensitive types.Set       `tfsdk:"response_headers_sensitive"`
+	ResponseHeaders          types.Map       `tfsdk:"response_headers"`
+	SensitiveResponseHeaders types.Map       `tfsdk:"sensitive_response_headers"`
+	Resolve                  types.Map       `tfsdk:"resolve"`
+	DNSServers               types.List      `tfsdk:"dns_servers"`
+	UnixSocketPath           types.String    `tfsdk:"unix_socket_path"`
+	RemoteIP                 types.String    `tfsdk:"remote_ip"`
+	MaxResponseBytes         types.Int64     `tfsdk:"max_response_bytes"`
+	TruncateResponse         types.Bool      `tfsdk:"truncate_response"`
+	ResponseTruncated        types.Bool      `tfsdk:"response_truncated"`
+	ExpectedChecksum         types.String    `tfsdk:"expected_checksum"`
+	Cache                    types.Bool      `tfsdk:"cache"`
+	Proxy                    *proxyModel     `tfsdk:"proxy"`
+	FromCache                types.Bool      `tfsdk:"from_cache"`
+	ResponseBodyMD5          types.String    `tfsdk:"response_body_md5"`
+	ResponseBodySHA256       types.String    `tfsdk:"response_body_sha256"`
+	ResponseBodySHA512       types.String    `tfsdk:"response_body_sha512"`
+	FollowRedirects          types.Bool      `tfsdk:"follow_redirects"`
+	MaxRedirects             types.Int64     `tfsdk:"max_redirects"`
+	AllowCrossHostRedirects  types.Bool      `tfsdk:"allow_cross_host_redirects"`
+	AllowInsecureRedirects   types.Bool      `tfsdk:"allow_insecure_redirects"`
+	FinalURL                 types.String    `tfsdk:"final_url"`
+	RedirectChain            types.List      `tfsdk:"redirect_chain"`
+	AllowedMethods           types.List      `tfsdk:"allowed_methods"`
+	ExpectedStatusCodes      types.List      `tfsdk:"expected_status_codes"`
+	RequestTimeout           types.Int64     `tfsdk:"request_timeout_ms"`
+	ConnectTimeout           types.Int64     `tfsdk:"connect_timeout_ms"`
+	TLSHandshakeTimeout      types.Int64     `tfsdk:"tls_handshake_timeout_ms"`
+	ResponseHeaderTimeout    types.Int64     `tfsdk:"response_header_timeout_ms"`
+	CaCertificate            types.String    `tfsdk:"ca_cert_pem"`
+	Insecure                 types.Bool      `tfsdk:"insecure"`
+	ClientCertificate        types.String    `tfsdk:"client_cert_pem"`
+	ClientKey                types.String    `tfsdk:"client_key_pem"`
+	ClientPKCS12             types.String    `tfsdk:"client_pkcs12_base64"`
+	ClientKeyPassword        types.String    `tfsdk:"client_key_password"`
+	TLSMinVersion            types.String    `tfsdk:"tls_min_version"`
+	TLSMaxVersion            types.String    `tfsdk:"tls_max_version"`
+	TLSCipherSuites          types.List      `tfsdk:"tls_cipher_suites"`
+	TLSServerName            types.String    `tfsdk:"tls_server_name"`
+	TLSALPNProtocols         types.List      `tfsdk:"tls_alpn_protocols"`
+	ResponseBody             types.String    `tfsdk:"response_body"`
+	ResponseBodyCharset      types.String    `tfsdk:"response_body_charset"`
+	ResponseBodyBase64       types.String    `tfsdk:"response_body_base64"`
+	ResponseJSON             dynamicValue    `tfsdk:"response_json"`
+	SensitiveResponseBody    types.String    `tfsdk:"sensitive_response_body"`
+	Body                     types.String    `tfsdk:"body"`
+	StatusCode               types.Int64     `tfsdk:"status_code"`
+	RequestAttempts          types.Int64     `tfsdk:"request_attempts"`
+	Auth                     *authModel      `tfsdk:"auth"`
+	Retry                    *retryModel     `tfsdk:"retry"`
+}
+
+type retryModel struct {
+	Attempts        types.Int64 `tfsdk:"attempts"`
+	MinDelay        types.Int64 `tfsdk:"min_delay_ms"`
+	MaxDelay        types.Int64 `tfsdk:"max_delay_ms"`
+	StatusCodes     types.Set   `tfsdk:"status_codes"`
+	TransportErrors types.Set   `tfsdk:"transport_errors"`
 }
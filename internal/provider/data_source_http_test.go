@@ -1,19 +1,30 @@
 package provider
 
 import (
+	"bufio"
+	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
 	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"golang.org/x/net/dns/dnsmessage"
 )
 
 func TestDataSource_200(t *testing.T) {
@@ -30,6 +41,7 @@ func TestDataSource_200(t *testing.T) {
 							}`, testHttpMock.server.URL),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "1.0.0"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body_base64", "MS4wLjA="),
 					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.Content-Type", "text/plain"),
 					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Single", "foobar"),
 					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Double", "1, 2"),
@@ -86,6 +98,466 @@ func TestDataSource_withAuthorizationRequestHeader_200(t *testing.T) {
 	})
 }
 
+func TestDataSource_AuthBasic(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+
+								auth {
+									basic {
+										username = "user"
+										password = "secret"
+									}
+								}
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_AuthBearer(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+
+								request_headers = {
+									Authorization = "Basic Zm9vOmJhcg=="
+								}
+
+								auth {
+									bearer {
+										token = "token"
+									}
+								}
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_AuthDigest(t *testing.T) {
+	challenge := map[string]string{
+		"realm":     "test",
+		"nonce":     "dcd98b7102dd2f0e8b11d0f600bfb0c093",
+		"qop":       "auth",
+		"algorithm": "SHA-256",
+	}
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var authorization map[string]string
+		for _, c := range parseChallenges(r.Header.Values("Authorization")) {
+			authorization = c.params
+		}
+
+		expected, _ := digestAuthorization(challenge, "user", "secret", r.Method, r.URL.RequestURI(), authorization["cnonce"])
+		if r.Header.Get("Authorization") != expected {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test", Digest realm="test", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", qop="auth", algorithm=SHA-256`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url          = "%s/digest?a=b"
+								method       = "POST"
+								request_body = "request body"
+
+								auth {
+									digest {
+										username = "user"
+										password = "secret"
+									}
+								}
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "request body"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s/digest"
+
+								auth {
+									digest {
+										username = "user"
+										password = "wrong"
+									}
+								}
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "401"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_AuthOAuth2ClientCredentials(t *testing.T) {
+	var tokenRequests int
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			_ = r.ParseForm()
+			if clientID, clientSecret, _ := r.BasicAuth(); clientID != "client" || clientSecret != "secret" ||
+				r.PostForm.Get("scope") != "read write" || r.PostForm.Get("audience") != "api" || r.PostForm.Get("resource") != "items" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token": "token", "token_type": "bearer", "expires_in": 3600}`))
+		default:
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							locals {
+								oauth2 = {
+									token_url     = "%[1]s/token"
+									client_id     = "client"
+									client_secret = "secret"
+									scopes        = ["read", "write"]
+									audience      = "api"
+									extra_params  = { resource = "items" }
+								}
+							}
+
+							data "http" "first" {
+								url = "%[1]s/first"
+
+								auth {
+									oauth2_client_credentials {
+										token_url     = local.oauth2.token_url
+										client_id     = local.oauth2.client_id
+										client_secret = local.oauth2.client_secret
+										scopes        = local.oauth2.scopes
+										audience      = local.oauth2.audience
+										extra_params  = local.oauth2.extra_params
+									}
+								}
+							}
+
+							data "http" "second" {
+								url = "%[1]s/second"
+
+								auth {
+									oauth2_client_credentials {
+										token_url     = local.oauth2.token_url
+										client_id     = local.oauth2.client_id
+										client_secret = local.oauth2.client_secret
+										scopes        = local.oauth2.scopes
+										audience      = local.oauth2.audience
+										extra_params  = local.oauth2.extra_params
+									}
+								}
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.first", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.second", "status_code", "200"),
+					func(_ *terraform.State) error {
+						if tokenRequests != 1 {
+							return fmt.Errorf("expected 1 token request, got %d", tokenRequests)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_AuthOAuth2ClientCredentialsError(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": "invalid_client"}`))
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%[1]s"
+
+								auth {
+									oauth2_client_credentials {
+										token_url     = "%[1]s/token"
+										client_id     = "client"
+										client_secret = "wrong"
+									}
+								}
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile("invalid_client"),
+			},
+		},
+	})
+}
+
+func TestDataSource_AuthAWSSigV4(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENVIRONMENT")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "environment-secret")
+	t.Setenv("AWS_SESSION_TOKEN", "environment-token")
+	t.Setenv("AWS_REGION", "eu-west-1")
+
+	// The stub verifies the signature by signing a copy of the request with the
+	// credentials it expects, at the time of the signature.
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		signer := &awsSigV4Signer{
+			region:    "us-east-1",
+			service:   "execute-api",
+			accessKey: "AKIDEXAMPLE",
+			secretKey: "static-secret",
+		}
+		if strings.Contains(r.Header.Get("Authorization"), "AKIDENVIRONMENT") {
+			signer = &awsSigV4Signer{
+				region:       "eu-west-1",
+				service:      "execute-api",
+				accessKey:    "AKIDENVIRONMENT",
+				secretKey:    "environment-secret",
+				sessionToken: "environment-token",
+			}
+		}
+
+		signedAt, err := time.Parse(awsSigV4TimeFormat, r.Header.Get("X-Amz-Date"))
+		if err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		expected, _ := http.NewRequest(r.Method, "http://"+r.Host+r.RequestURI, strings.NewReader(string(body)))
+		if contentType := r.Header.Get("Content-Type"); contentType != "" {
+			expected.Header.Set("Content-Type", contentType)
+		}
+		if err := signer.sign(expected, signedAt); err != nil || r.Header.Get("Authorization") != expected.Header.Get("Authorization") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url          = "%s/prod/items?b=2&a=1"
+								method       = "POST"
+								request_body = "{\"name\": \"example\"}"
+
+								request_headers = {
+									Content-Type = "application/json"
+								}
+
+								auth {
+									aws_sigv4 {
+										region     = "us-east-1"
+										service    = "execute-api"
+										access_key = "AKIDEXAMPLE"
+										secret_key = "static-secret"
+									}
+								}
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", `{"name": "example"}`),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s/prod/items"
+
+								auth {
+									aws_sigv4 {
+										service = "execute-api"
+									}
+								}
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_AuthCrossHostRedirect(t *testing.T) {
+	var authorization string
+
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer other.Close()
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL, http.StatusFound)
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+
+								auth {
+									bearer {
+										token = "token"
+									}
+								}
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					func(_ *terraform.State) error {
+						if authorization != "" {
+							return fmt.Errorf("expected no Authorization header after redirect, got %q", authorization)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_AuthInsecureRedirect(t *testing.T) {
+	var authorization string
+
+	svr := newTestMixedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil {
+			http.Redirect(w, r, "http://"+r.Host+"/plain", http.StatusFound)
+			return
+		}
+
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url      = "https://%s"
+								insecure = true
+
+								auth {
+									bearer {
+										token = "token"
+									}
+								}
+							}`, svr.Listener.Addr()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "final_url", fmt.Sprintf("http://%s/plain", svr.Listener.Addr())),
+					func(_ *terraform.State) error {
+						if authorization != "" {
+							return fmt.Errorf("expected no Authorization header after redirect to http, got %q", authorization)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_AuthConflict(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+							data "http" "http_test" {
+								url = "http://localhost"
+
+								auth {
+									basic {
+										username = "user"
+										password = "secret"
+									}
+
+									bearer {
+										token = "token"
+									}
+								}
+							}`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+		},
+	})
+}
+
 func TestDataSource_withAuthorizationRequestHeader_403(t *testing.T) {
 	testHttpMock := setUpMockHttpServer(false)
 	defer testHttpMock.server.Close()
@@ -96,24 +568,1826 @@ func TestDataSource_withAuthorizationRequestHeader_403(t *testing.T) {
 			{
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
-  								url = "%s/restricted"
-
-  								request_headers = {
-    								"Authorization" = "unauthorized"
-  								}
-							}`, testHttpMock.server.URL),
+  								url = "%s/restricted"
+
+  								request_headers = {
+    								"Authorization" = "unauthorized"
+  								}
+							}`, testHttpMock.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", ""),
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "403"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_utf8_200(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(false)
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+  								url = "%s/utf-8/200"
+							}`, testHttpMock.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "1.0.0"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.Content-Type", "text/plain; charset=UTF-8"),
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_utf16_200(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(false)
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+  								url = "%s/utf-16/200"
+							}`, testHttpMock.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", `"1.0.0"`),
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_iso88591_200(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(false)
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+  								url = "%s/iso-8859-1/200"
+							}`, testHttpMock.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "café"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body_base64", "Y2Fm6Q=="),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_ResponseBodyCharset(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("\x93\xfa\x96\x7b"))
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url                   = "%s"
+								response_body_charset = "shift_jis"
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "日本"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url                   = "%s"
+								response_body_charset = "x-unknown"
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile(`unsupported charset "x-unknown"`),
+			},
+		},
+	})
+}
+
+func TestDataSource_Binary_200(t *testing.T) {
+	body := []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0xff}
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body_base64", base64.StdEncoding.EncodeToString(body)),
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_ResponseJSON(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"name": "example", "tags": ["a", "b"], "count": 2, "parent": null}`))
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+							}
+
+							output "name" {
+								value = data.http.http_test.response_json.name
+							}
+
+							output "tag" {
+								value = data.http.http_test.response_json.tags[1]
+							}
+
+							output "count" {
+								value = data.http.http_test.response_json.count
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("name", "example"),
+					resource.TestCheckOutput("tag", "b"),
+					resource.TestCheckOutput("count", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_ResponseJSONNotJSON(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(false)
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s/200"
+							}`, testHttpMock.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("data.http.http_test", "response_json"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_ResponseJSONInvalid(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"name": `))
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile("Error decoding JSON response body"),
+			},
+		},
+	})
+}
+
+func TestDataSource_SensitiveRequest(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Api-Key") != "secret" || r.Header.Get("X-Other") != "plain" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url    = "%s"
+								method = "POST"
+
+								request_headers = {
+									X-Api-Key = "overridden"
+									X-Other   = "plain"
+								}
+
+								sensitive_request_headers = {
+									X-Api-Key = "secret"
+								}
+
+								sensitive_request_body = "password=secret"
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "password=secret"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url                    = "%s"
+								request_body           = "plain"
+								sensitive_request_body = "secret"
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+		},
+	})
+}
+
+func TestDataSource_RequestForm(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, "%s;%s;%s", r.Header.Get("Content-Type"), r.PostForm.Get("name"), r.PostForm.Get("query"))
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url    = "%s"
+								method = "POST"
+
+								request_form = {
+									name  = "terraform"
+									query = "a&b=c"
+								}
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "application/x-www-form-urlencoded;terraform;a&b=c"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url          = "%s"
+								request_body = "plain"
+
+								request_form = {
+									name = "terraform"
+								}
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+		},
+	})
+}
+
+func TestDataSource_RequestMultipart(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		parts := []string{r.MultipartForm.Value["field"][0]}
+		for _, name := range []string{"text", "binary", "source"} {
+			file := r.MultipartForm.File[name][0]
+			f, err := file.Open()
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			content, _ := io.ReadAll(f)
+			f.Close()
+			parts = append(parts, fmt.Sprintf("%s:%s:%s", file.Filename, file.Header.Get("Content-Type"), content))
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(strings.Join(parts, ";")))
+	}))
+	defer svr.Close()
+
+	source := filepath.Join(t.TempDir(), "report.csv")
+	if err := os.WriteFile(source, []byte("a,b"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url    = "%s"
+								method = "POST"
+
+								request_multipart {
+									fields = {
+										field = "value"
+									}
+
+									file {
+										name     = "text"
+										filename = "hello.txt"
+										content  = "hello"
+									}
+
+									file {
+										name           = "binary"
+										filename       = "data.bin"
+										content_type   = "image/png"
+										content_base64 = "aGk="
+									}
+
+									file {
+										name         = "source"
+										content_type = "text/csv"
+										source       = %q
+									}
+								}
+							}`, svr.URL, source),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body",
+						"value;hello.txt:application/octet-stream:hello;data.bin:image/png:hi;report.csv:text/csv:a,b"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+
+								request_multipart {
+									file {
+										name    = "text"
+										content = "hello"
+										source  = %q
+									}
+								}
+							}`, svr.URL, source),
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+
+								request_multipart {
+									file {
+										name   = "source"
+										source = %q
+									}
+								}
+							}`, svr.URL, source+".missing"),
+				ExpectError: regexp.MustCompile("Error reading file"),
+			},
+		},
+	})
+}
+
+func TestDataSource_RequestMultipartDefaultFilename(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader, err := r.MultipartReader()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var parts []string
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			parts = append(parts, fmt.Sprintf("%s:%s", part.FormName(), part.FileName()))
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(strings.Join(parts, ";")))
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url    = "%s"
+								method = "POST"
+
+								request_multipart {
+									fields = {
+										field = "value"
+									}
+
+									file {
+										name    = "text"
+										content = "hello"
+									}
+
+									file {
+										name           = "binary"
+										content_base64 = "aGk="
+									}
+								}
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "field:;text:text;binary:binary"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_SensitiveResponse(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("X-Request-Id", "1")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"token": "secret"}`))
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url                        = "%s"
+								response_body_sensitive    = true
+								response_headers_sensitive = ["set-cookie"]
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("data.http.http_test", "response_body"),
+					resource.TestCheckNoResourceAttr("data.http.http_test", "response_body_base64"),
+					resource.TestCheckNoResourceAttr("data.http.http_test", "body"),
+					resource.TestCheckNoResourceAttr("data.http.http_test", "response_body_md5"),
+					resource.TestCheckNoResourceAttr("data.http.http_test", "response_body_sha256"),
+					resource.TestCheckNoResourceAttr("data.http.http_test", "response_body_sha512"),
+					resource.TestCheckResourceAttr("data.http.http_test", "sensitive_response_body", `{"token": "secret"}`),
+					resource.TestCheckNoResourceAttr("data.http.http_test", "response_headers.Set-Cookie"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Request-Id", "1"),
+					resource.TestCheckResourceAttr("data.http.http_test", "sensitive_response_headers.%", "1"),
+					resource.TestCheckResourceAttr("data.http.http_test", "sensitive_response_headers.Set-Cookie", "session=secret"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_SensitiveResponseInvalidJSON(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`token=secret`))
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url                     = "%s"
+								response_body_sensitive = true
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("data.http.http_test", "response_json"),
+					resource.TestCheckResourceAttr("data.http.http_test", "sensitive_response_body", "token=secret"),
+				),
+			},
+		},
+	})
+}
+
+// TODO: This test fails under Terraform 0.14. It should be uncommented when we
+// are able to include Terraform version logic within acceptance tests
+// (see https://github.com/hashicorp/terraform-plugin-sdk/issues/776), or when
+// 0.14 is removed from the test matrix (see
+// https://github.com/hashicorp/terraform-provider-http/pull/74).
+//
+//func TestDataSource_x509cert(t *testing.T) {
+//	testHttpMock := setUpMockHttpServer(false)
+//	defer testHttpMock.server.Close()
+//
+//	resource.UnitTest(t, resource.TestCase{
+//		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+//		Steps: []resource.TestStep{
+//			{
+//				Config: fmt.Sprintf(`
+//							data "http" "http_test" {
+//  								url = "%s/x509-ca-cert/200"
+//							}`, testHttpMock.server.URL),
+//				Check: resource.ComposeTestCheckFunc(
+//					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "pem"),
+//					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+//				),
+//			},
+//		},
+//	})
+//}
+
+func TestDataSource_UpgradeFromVersion2_2_0(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(false)
+	defer testHttpMock.server.Close()
+
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				ExternalProviders: map[string]resource.ExternalProvider{
+					"http": {
+						VersionConstraint: "2.2.0",
+						Source:            "hashicorp/http",
+					},
+				},
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s/200"
+							}`, testHttpMock.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "1.0.0"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.Content-Type", "text/plain"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Single", "foobar"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Double", "1, 2"),
+				),
+			},
+			{
+				ProtoV5ProviderFactories: protoV5ProviderFactories(),
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s/200"
+							}`, testHttpMock.server.URL),
+				PlanOnly: true,
+			},
+			{
+				ProtoV5ProviderFactories: protoV5ProviderFactories(),
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s/200"
+							}`, testHttpMock.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "1.0.0"),
+					resource.TestCheckResourceAttr("data.http.http_test", "body", "1.0.0"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.Content-Type", "text/plain"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Single", "foobar"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Double", "1, 2"),
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_Provisioner(t *testing.T) {
+	t.Parallel()
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A Content-Type that does not raise a warning in the Read function must be set in
+		// order to prevent test failure under TF 0.14.x as warnings result in no output
+		// being written which causes the local-exec command to fail with "Error:
+		// local-exec provisioner command must be a non-empty string".
+		// See https://github.com/hashicorp/terraform-provider-http/pull/74
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer svr.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		ExternalProviders: map[string]resource.ExternalProvider{
+			"null": {
+				VersionConstraint: "3.1.1",
+				Source:            "hashicorp/null",
+			},
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+							}
+							resource "null_resource" "example" {
+  								provisioner "local-exec" {
+    								command = contains([201, 204], data.http.http_test.status_code)
+  								}
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile(`Error running command 'false': exit status 1. Output:`),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+							}
+							resource "null_resource" "example" {
+  								provisioner "local-exec" {
+    								command = contains([200], data.http.http_test.status_code)
+  								}
+							}`, svr.URL),
+				Check: resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+			},
+		},
+	})
+}
+
+func TestDataSource_POST_201(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(false)
+
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+ 								url = "%s/create"
+								method = "POST" 
+							}`, testHttpMock.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "created"),
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "201"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_HEAD_204(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(false)
+
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+ 								url = "%s/head"
+								method = "HEAD" 
+							}`, testHttpMock.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.Content-Type", "text/plain"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Single", "foobar"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Double", "1, 2"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", ""),
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_MethodOptions(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodOptions {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Add("Allow", "GET, HEAD")
+		w.Header().Add("Allow", "OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url    = "%s"
+								method = "OPTIONS"
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "204"),
+					resource.TestCheckResourceAttr("data.http.http_test", "allowed_methods.#", "3"),
+					resource.TestCheckResourceAttr("data.http.http_test", "allowed_methods.0", "GET"),
+					resource.TestCheckResourceAttr("data.http.http_test", "allowed_methods.1", "HEAD"),
+					resource.TestCheckResourceAttr("data.http.http_test", "allowed_methods.2", "OPTIONS"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "405"),
+					resource.TestCheckNoResourceAttr("data.http.http_test", "allowed_methods"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_MethodQuery(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, "%s %s", r.Method, body)
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url          = "%s"
+								method       = "QUERY"
+								request_body = "select name"
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "QUERY select name"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_UnsupportedMethod(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(false)
+
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+ 								url = "%s/200"
+								method = "DELETE" 
+							}`, testHttpMock.server.URL),
+				ExpectError: regexp.MustCompile(`.*value must be one of:\s+\["\\"GET\\""\s+"\\"HEAD\\""\s+"\\"OPTIONS\\""\s+"\\"POST\\""\s+"\\"QUERY\\""`),
+			},
+		},
+	})
+}
+
+func TestDataSource_WithCACertificate(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(true)
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+  								url = "%s/200"
+
+  								ca_cert_pem = <<EOF
+%s
+EOF
+							}`, testHttpMock.server.URL, CertToPEM(testHttpMock.server.Certificate())),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_WithCACertificateFalse(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(true)
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+  								url = "%s/200"
+
+  								ca_cert_pem = "invalid"
+							}`, testHttpMock.server.URL),
+				ExpectError: regexp.MustCompile(`Can't add the CA certificate to certificate pool. Only PEM encoded\ncertificates are supported.`),
+			},
+		},
+	})
+}
+
+func TestDataSource_InsecureTrue(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(true)
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+  								url = "%s/200"
+
+  								insecure = true
+							}`, testHttpMock.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_InsecureFalse(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(true)
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+  								url = "%s/200"
+
+  								insecure = false
+							}`, testHttpMock.server.URL),
+				ExpectError: regexp.MustCompile(fmt.Sprintf(`Error making request: Get "%s/200": x509: `, testHttpMock.server.URL)),
+			},
+		},
+	})
+}
+
+func TestDataSource_InsecureUnconfigured(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(true)
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+  								url = "%s/200"
+							}`, testHttpMock.server.URL),
+				ExpectError: regexp.MustCompile(fmt.Sprintf(`Error making request: Get "%s/200": x509: `, testHttpMock.server.URL)),
+			},
+		},
+	})
+}
+
+func TestDataSource_UnsupportedInsecureCaCert(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(true)
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+ 								url = "%s/200"
+								insecure = true
+								ca_cert_pem = "invalid"
+							}`, testHttpMock.server.URL),
+				ExpectError: regexp.MustCompile(`Attribute "insecure" cannot be specified when "ca_cert_pem" is specified`),
+			},
+		},
+	})
+}
+
+// testProxiedURL is a hardcoded URL used in acceptance testing where it is
+// expected that a locally started HTTP proxy will handle the request.
+//
+// Neither localhost nor the loopback interface (127.0.0.1) can be used for the
+// address of the server as httpproxy/proxy.go will ignore these addresses.
+//
+// References:
+//   - https://cs.opensource.google/go/x/net/+/internal-branch.go1.19-vendor:http/httpproxy/proxy.go;l=181
+//   - https://cs.opensource.google/go/x/net/+/internal-branch.go1.19-vendor:http/httpproxy/proxy.go;l=186
+const testProxiedURL = "http://terraform-provider-http-test-proxy"
+
+func TestDataSource_HTTPViaProxyWithEnv(t *testing.T) {
+	proxyRequests := 0
+	serverRequests := 0
+
+	// Content-Type is set to text/plain otherwise the http data source issues a warning which
+	// causes Terraform 0.14 to not write any data to state.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverRequests++
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+
+	if err != nil {
+		t.Fatalf("error parsing server URL: %s", err)
+	}
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxyRequests++
+		httputil.NewSingleHostReverseProxy(serverURL).ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	t.Setenv("HTTP_PROXY", proxy.URL)
+	t.Setenv("HTTPS_PROXY", proxy.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					data "http" "http_test" {
+						url = "%s"
+					}
+				`, testProxiedURL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					CheckServerAndProxyRequestCount(&proxyRequests, &serverRequests),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_Proxy(t *testing.T) {
+	var proxyRequests, serverRequests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverRequests++
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("error parsing server URL: %s", err)
+	}
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Proxy-Authorization") != "Basic "+base64.StdEncoding.EncodeToString([]byte("user:secret")) {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		proxyRequests++
+		httputil.NewSingleHostReverseProxy(serverURL).ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	// The data source proxy takes precedence over the environment.
+	t.Setenv("HTTP_PROXY", "http://127.0.0.1:1")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+
+								proxy {
+									url      = "%s"
+									username = "user"
+									password = "secret"
+								}
+							}`, testProxiedURL, proxy.URL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					CheckServerAndProxyRequestCount(&proxyRequests, &serverRequests),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+
+								proxy {
+									url = "ftp://%s"
+								}
+							}`, testProxiedURL, serverURL.Host),
+				ExpectError: regexp.MustCompile("Invalid proxy configuration"),
+			},
+		},
+	})
+}
+
+func TestDataSource_ProxySOCKS5(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(r.Host))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("error parsing server URL: %s", err)
+	}
+
+	proxyAddr, requested := startSOCKS5Proxy(t, "user", "secret", serverURL.Host)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+
+								proxy {
+									url      = "socks5h://%s"
+									username = "user"
+									password = "secret"
+								}
+							}`, testProxiedURL, proxyAddr),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "terraform-provider-http-test-proxy"),
+					func(_ *terraform.State) error {
+						// The host name is resolved by the proxy.
+						if addr := <-requested; addr != "terraform-provider-http-test-proxy:80" {
+							return fmt.Errorf("expected the proxy to be requested the host name, got %q", addr)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+
+								proxy {
+									url      = "socks5h://%s"
+									username = "user"
+									password = "wrong"
+								}
+							}`, testProxiedURL, proxyAddr),
+				ExpectError: regexp.MustCompile("authentication failed"),
+			},
+		},
+	})
+}
+
+func TestDataSource_Resolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(r.Host))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("error parsing server URL: %s", err)
+	}
+
+	_, port, err := net.SplitHostPort(serverURL.Host)
+	if err != nil {
+		t.Fatalf("error parsing server address: %s", err)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "http://www.example.com:%[1]s/"
+
+								resolve = {
+									"www.example.com:%[1]s" = "127.0.0.1"
+								}
+							}`, port),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "www.example.com:"+port),
+					resource.TestCheckResourceAttr("data.http.http_test", "remote_ip", "127.0.0.1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+
+								resolve = {
+									"www.example.com" = "127.0.0.1"
+								}
+							}`, server.URL),
+				ExpectError: regexp.MustCompile("Invalid resolve address"),
+			},
+		},
+	})
+}
+
+func TestDataSource_ResolveCache(t *testing.T) {
+	newServer := func(ip, port, body string) *httptest.Server {
+		listener, err := net.Listen("tcp", net.JoinHostPort(ip, port))
+		if err != nil {
+			t.Fatalf("error listening on %s: %s", ip, err)
+		}
+
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(body))
+		}))
+		server.Listener.Close()
+		server.Listener = listener
+		server.Start()
+
+		return server
+	}
+
+	first := newServer("127.0.0.1", "0", "first")
+	defer first.Close()
+
+	_, port, err := net.SplitHostPort(first.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("error parsing server address: %s", err)
+	}
+
+	second := newServer("127.0.0.2", port, "second")
+	defer second.Close()
+
+	cacheDir := t.TempDir()
+
+	config := func(ip string) string {
+		return fmt.Sprintf(`
+							provider "http" {
+								cache_dir    = %q
+								cache_ttl_ms = 3600000
+							}
+
+							data "http" "http_test" {
+								url   = "http://www.example.com:%[2]s/"
+								cache = true
+
+								resolve = {
+									"www.example.com:%[2]s" = "%[3]s"
+								}
+							}`, cacheDir, port, ip)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: config("127.0.0.1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "first"),
+					resource.TestCheckResourceAttr("data.http.http_test", "from_cache", "false"),
+				),
+			},
+			{
+				Config: config("127.0.0.1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "first"),
+					resource.TestCheckResourceAttr("data.http.http_test", "from_cache", "true"),
+					resource.TestCheckResourceAttr("data.http.http_test", "remote_ip", "127.0.0.1"),
+				),
+			},
+			{
+				// The response cached for another server is not used.
+				Config: config("127.0.0.2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "second"),
+					resource.TestCheckResourceAttr("data.http.http_test", "from_cache", "false"),
+					resource.TestCheckResourceAttr("data.http.http_test", "remote_ip", "127.0.0.2"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_DNSServers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(r.Host))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("error parsing server URL: %s", err)
+	}
+
+	_, port, err := net.SplitHostPort(serverURL.Host)
+	if err != nil {
+		t.Fatalf("error parsing server address: %s", err)
+	}
+
+	dnsServer := startDNSServer(t, map[string]string{"backend.example.test": "127.0.0.1"})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url         = "http://backend.example.test:%s/"
+								dns_servers = ["%s"]
+							}`, port, dnsServer),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "backend.example.test:"+port),
+					resource.TestCheckResourceAttr("data.http.http_test", "remote_ip", "127.0.0.1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url         = "http://missing.example.test:%s/"
+								dns_servers = ["%s"]
+							}`, port, dnsServer),
+				ExpectError: regexp.MustCompile("no such host"),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url         = "%s"
+								dns_servers = ["dns.example.com"]
+							}`, server.URL),
+				ExpectError: regexp.MustCompile("Invalid DNS server"),
+			},
+		},
+	})
+}
+
+func TestDataSource_UnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "http.sock")
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("error listening on Unix socket: %s", err)
+	}
+
+	server := &httptest.Server{
+		Listener: listener,
+		Config: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(r.Host + r.URL.Path))
+		})},
+	}
+	server.Start()
+	defer server.Close()
+
+	// The proxy from the environment is not used for the socket.
+	t.Setenv("HTTP_PROXY", "http://127.0.0.1:1")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url              = "http://docker/v1.43/version"
+								unix_socket_path = "%s"
+							}`, socketPath),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "docker/v1.43/version"),
+					resource.TestCheckNoResourceAttr("data.http.http_test", "remote_ip"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url              = "http://docker/"
+								unix_socket_path = "%s.missing"
+							}`, socketPath),
+				ExpectError: regexp.MustCompile("no such file or directory"),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url              = "http://docker/"
+								unix_socket_path = "%s"
+
+								proxy {
+									url = "http://127.0.0.1:1"
+								}
+							}`, socketPath),
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+		},
+	})
+}
+
+func TestDataSource_UnixSocketOtherHosts(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "http.sock")
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("error listening on Unix socket: %s", err)
+	}
+
+	server := &httptest.Server{
+		Listener: listener,
+		Config: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("hello"))
+		})},
+	}
+	server.Start()
+	defer server.Close()
+
+	// The token and the checksums file are requested from this server rather
+	// than through the socket.
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token": "token", "token_type": "bearer", "expires_in": 3600}`))
+		case "/SHA256SUMS":
+			_, _ = w.Write([]byte("2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824  hello.txt\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer other.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url               = "http://localhost/hello.txt"
+								unix_socket_path  = "%[1]s"
+								expected_checksum = "%[2]s/SHA256SUMS"
+
+								auth {
+									oauth2_client_credentials {
+										token_url     = "%[2]s/token"
+										client_id     = "client"
+										client_secret = "secret"
+									}
+								}
+							}`, socketPath, other.URL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "hello"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_MaxResponseBytes(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		if r.URL.Path == "/chunked" {
+			// Flushing before writing the body prevents the Content-Length
+			// header from being set.
+			w.(http.Flusher).Flush()
+		}
+		_, _ = w.Write([]byte("1234567890"))
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url                = "%s"
+								max_response_bytes = 10
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "1234567890"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_truncated", "false"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url                = "%s/chunked"
+								max_response_bytes = 4
+								truncate_response  = true
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "1234"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_truncated", "true"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url                = "%s"
+								max_response_bytes = 4
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile(`Response body too large`),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url                = "%s/chunked"
+								max_response_bytes = 4
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile(`Response body too large`),
+			},
+		},
+	})
+}
+
+func TestDataSource_Checksum(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Path == "/SHA256SUMS" {
+			_, _ = w.Write([]byte("2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824  hello.txt\n"))
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url               = "%s/hello.txt"
+								expected_checksum = "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body_md5", "5d41402abc4b2a76b9719d911017c592"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body_sha256", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body_sha512", "9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url               = "%s/hello.txt"
+								expected_checksum = "%s/SHA256SUMS"
+							}`, svr.URL, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "hello"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url               = "%s/hello.txt"
+								expected_checksum = "md5:00000000000000000000000000000000"
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile("Checksum mismatch"),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url               = "%s/other.txt"
+								expected_checksum = "%s/SHA256SUMS"
+							}`, svr.URL, svr.URL),
+				ExpectError: regexp.MustCompile("Error reading expected checksum"),
+			},
+		},
+	})
+}
+
+func TestDataSource_ChecksumCACertificate(t *testing.T) {
+	svr := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Path == "/SHA256SUMS" {
+			_, _ = w.Write([]byte("2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824  hello.txt\n"))
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer svr.Close()
+
+	// The checksums file is requested with the ca_cert_pem of the data source.
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url               = "%[1]s/hello.txt"
+								expected_checksum = "%[1]s/SHA256SUMS"
+
+								ca_cert_pem = <<EOF
+%[2]s
+EOF
+							}`, svr.URL, CertToPEM(svr.Certificate())),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "hello"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_Cache(t *testing.T) {
+	var notModified int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("1.0.0"))
+	}))
+	defer svr.Close()
+
+	config := fmt.Sprintf(`
+							provider "http" {
+								cache_dir = %q
+							}
+
+							data "http" "http_test" {
+								url   = "%s"
+								cache = true
+							}`, t.TempDir(), svr.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "1.0.0"),
+				),
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "1.0.0"),
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "from_cache", "true"),
+					func(_ *terraform.State) error {
+						if atomic.LoadInt32(&notModified) == 0 {
+							return fmt.Errorf("expected a conditional request")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_CacheAuth(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, _, _ := r.BasicAuth()
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Set-Cookie", "session="+username)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(username))
+	}))
+	defer svr.Close()
+
+	cacheDir := t.TempDir()
+
+	config := func(username, extra string) string {
+		return fmt.Sprintf(`
+							provider "http" {
+								cache_dir    = %q
+								cache_ttl_ms = 3600000
+							}
+
+							data "http" "http_test" {
+								url   = "%s"
+								cache = true
+								%s
+
+								auth {
+									basic {
+										username = "%s"
+										password = "password"
+									}
+								}
+							}`, cacheDir, svr.URL, extra, username)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: config("first", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "first"),
+					resource.TestCheckResourceAttr("data.http.http_test", "from_cache", "false"),
+				),
+			},
+			{
+				Config: config("first", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "first"),
+					resource.TestCheckResourceAttr("data.http.http_test", "from_cache", "true"),
+				),
+			},
+			{
+				// The response cached for other credentials is not used.
+				Config: config("second", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "second"),
+					resource.TestCheckResourceAttr("data.http.http_test", "from_cache", "false"),
+				),
+			},
+			{
+				// Responses with sensitive headers are not cached.
+				Config: config("first", `response_headers_sensitive = ["set-cookie"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "sensitive_response_headers.Set-Cookie", "session=first"),
+					resource.TestCheckResourceAttr("data.http.http_test", "from_cache", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_CacheRedirect(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("new"))
+	}))
+	defer svr.Close()
+
+	cacheDir := t.TempDir()
+
+	config := func(extra string) string {
+		return fmt.Sprintf(`
+							provider "http" {
+								cache_dir    = %q
+								cache_ttl_ms = 3600000
+							}
+
+							data "http" "http_test" {
+								url   = "%s/old"
+								cache = true
+								%s
+							}`, cacheDir, svr.URL, extra)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "from_cache", "false"),
+					resource.TestCheckResourceAttr("data.http.http_test", "redirect_chain.#", "2"),
+					resource.TestCheckResourceAttr("data.http.http_test", "redirect_chain.0", svr.URL+"/old"),
+					resource.TestCheckResourceAttr("data.http.http_test", "redirect_chain.1", svr.URL+"/new"),
+				),
+			},
+			{
+				// The redirect chain is kept for a response used from the cache.
+				Config: config(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "from_cache", "true"),
+					resource.TestCheckResourceAttr("data.http.http_test", "final_url", svr.URL+"/new"),
+					resource.TestCheckResourceAttr("data.http.http_test", "redirect_chain.#", "2"),
+					resource.TestCheckResourceAttr("data.http.http_test", "redirect_chain.0", svr.URL+"/old"),
+					resource.TestCheckResourceAttr("data.http.http_test", "redirect_chain.1", svr.URL+"/new"),
+				),
+			},
+			{
+				// The response cached when following redirects is not used
+				// without following them.
+				Config: config("follow_redirects = false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "from_cache", "false"),
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "302"),
+					resource.TestCheckResourceAttr("data.http.http_test", "redirect_chain.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSource_QueryParams(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(r.URL.RawQuery))
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s/search?page=2"
+
+								query_params = {
+									q   = ["a b&c"]
+									tag = ["one", "two"]
+								}
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "request_url", svr.URL+"/search?page=2&q=a+b%26c&tag=one&tag=two"),
+					resource.TestCheckResourceAttr("data.http.http_test", "id", svr.URL+"/search?page=2&q=a+b%26c&tag=one&tag=two"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "page=2&q=a+b%26c&tag=one&tag=two"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s/search"
+							}`, svr.URL),
 				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "request_url", svr.URL+"/search"),
 					resource.TestCheckResourceAttr("data.http.http_test", "response_body", ""),
-					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "403"),
 				),
 			},
 		},
 	})
 }
 
-func TestDataSource_utf8_200(t *testing.T) {
-	testHttpMock := setUpMockHttpServer(false)
-	defer testHttpMock.server.Close()
+func TestDataSource_Redirect(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+
+		switch r.URL.Path {
+		case "/first":
+			http.Redirect(w, r, "/second", http.StatusFound)
+		case "/second":
+			http.Redirect(w, r, "/final", http.StatusMovedPermanently)
+		case "/final":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("1.0.0"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer svr.Close()
 
 	resource.UnitTest(t, resource.TestCase{
 		ProtoV5ProviderFactories: protoV5ProviderFactories(),
@@ -121,21 +2395,56 @@ func TestDataSource_utf8_200(t *testing.T) {
 			{
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
-  								url = "%s/utf-8/200"
-							}`, testHttpMock.server.URL),
+								url = "%s/first"
+							}`, svr.URL),
 				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "id", svr.URL+"/first"),
+					resource.TestCheckResourceAttr("data.http.http_test", "final_url", svr.URL+"/final"),
+					resource.TestCheckResourceAttr("data.http.http_test", "redirect_chain.#", "3"),
+					resource.TestCheckResourceAttr("data.http.http_test", "redirect_chain.0", svr.URL+"/first"),
+					resource.TestCheckResourceAttr("data.http.http_test", "redirect_chain.1", svr.URL+"/second"),
+					resource.TestCheckResourceAttr("data.http.http_test", "redirect_chain.2", svr.URL+"/final"),
 					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "1.0.0"),
-					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.Content-Type", "text/plain; charset=UTF-8"),
-					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
 				),
 			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s/first"
+
+								follow_redirects = false
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "final_url", svr.URL+"/first"),
+					resource.TestCheckResourceAttr("data.http.http_test", "redirect_chain.#", "1"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.Location", "/second"),
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "302"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s/first"
+
+								max_redirects = 1
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile(`stopped after 1 redirects`),
+			},
 		},
 	})
 }
 
-func TestDataSource_utf16_200(t *testing.T) {
-	testHttpMock := setUpMockHttpServer(false)
-	defer testHttpMock.server.Close()
+func TestDataSource_RedirectCrossHost(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer svr.Close()
 
 	resource.UnitTest(t, resource.TestCase{
 		ProtoV5ProviderFactories: protoV5ProviderFactories(),
@@ -143,148 +2452,251 @@ func TestDataSource_utf16_200(t *testing.T) {
 			{
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
-  								url = "%s/utf-16/200"
-							}`, testHttpMock.server.URL),
-				// This should now be a warning, but unsure how to test for it...
-				// ExpectWarning: regexp.MustCompile("Content-Type is not a text type. Got: application/json; charset=UTF-16"),
+								url = "%s"
+
+								allow_cross_host_redirects = false
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile(`refusing redirect from host`),
 			},
 		},
 	})
 }
 
-// TODO: This test fails under Terraform 0.14. It should be uncommented when we
-// are able to include Terraform version logic within acceptance tests
-// (see https://github.com/hashicorp/terraform-plugin-sdk/issues/776), or when
-// 0.14 is removed from the test matrix (see
-// https://github.com/hashicorp/terraform-provider-http/pull/74).
-//
-//func TestDataSource_x509cert(t *testing.T) {
-//	testHttpMock := setUpMockHttpServer()
-//	defer testHttpMock.server.Close()
-//
-//	resource.UnitTest(t, resource.TestCase{
-//		ProtoV5ProviderFactories: protoV5ProviderFactories(),
-//		Steps: []resource.TestStep{
-//			{
-//				Config: fmt.Sprintf(`
-//							data "http" "http_test" {
-//  								url = "%s/x509-ca-cert/200"
-//							}`, testHttpMock.server.URL),
-//				Check: resource.ComposeTestCheckFunc(
-//					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "pem"),
-//					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
-//				),
-//			},
-//		},
-//	})
-//}
-
-func TestDataSource_UpgradeFromVersion2_2_0(t *testing.T) {
+func TestDataSource_ExpectedStatusCodes(t *testing.T) {
 	testHttpMock := setUpMockHttpServer(false)
 	defer testHttpMock.server.Close()
 
-	resource.Test(t, resource.TestCase{
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
 		Steps: []resource.TestStep{
 			{
-				ExternalProviders: map[string]resource.ExternalProvider{
-					"http": {
-						VersionConstraint: "2.2.0",
-						Source:            "hashicorp/http",
-					},
-				},
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
 								url = "%s/200"
+
+								expected_status_codes = ["2xx"]
 							}`, testHttpMock.server.URL),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "1.0.0"),
-					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.Content-Type", "text/plain"),
-					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Single", "foobar"),
-					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Double", "1, 2"),
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
 				),
 			},
 			{
-				ProtoV5ProviderFactories: protoV5ProviderFactories(),
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
-								url = "%s/200"
+								url = "%s/restricted"
+
+								expected_status_codes = ["200", "404"]
 							}`, testHttpMock.server.URL),
-				PlanOnly: true,
+				ExpectError: regexp.MustCompile(`(?s)Unexpected response status code.*403 Forbidden.*X-Single: foobar`),
 			},
+		},
+	})
+}
+
+func TestDataSource_ExpectedStatusCodesInvalid(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
 			{
-				ProtoV5ProviderFactories: protoV5ProviderFactories(),
+				Config: `
+							data "http" "http_test" {
+								url = "http://localhost"
+
+								expected_status_codes = ["20x"]
+							}`,
+				ExpectError: regexp.MustCompile(`must be a status code such as 200, or a class of status codes such as 2xx`),
+			},
+		},
+	})
+}
+
+func TestDataSource_RetryStatusCode(t *testing.T) {
+	var requests int
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/plain")
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("1.0.0"))
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() { requests = 0 },
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
-								url = "%s/200"
-							}`, testHttpMock.server.URL),
+								url = "%s"
+
+								retry {
+									attempts     = 2
+									min_delay_ms = 1
+									max_delay_ms = 10
+								}
+							}`, svr.URL),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "1.0.0"),
-					resource.TestCheckResourceAttr("data.http.http_test", "body", "1.0.0"),
-					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.Content-Type", "text/plain"),
-					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Single", "foobar"),
-					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Double", "1, 2"),
 					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "request_attempts", "3"),
+				),
+			},
+			{
+				PreConfig: func() { requests = 0 },
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+
+								retry {
+									attempts     = 1
+									min_delay_ms = 1
+									max_delay_ms = 10
+								}
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "503"),
+					resource.TestCheckResourceAttr("data.http.http_test", "request_attempts", "2"),
+				),
+			},
+			{
+				PreConfig: func() { requests = 0 },
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+
+								retry {
+									attempts     = 2
+									min_delay_ms = 1
+									max_delay_ms = 10
+									status_codes = [500]
+								}
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "503"),
+					resource.TestCheckResourceAttr("data.http.http_test", "request_attempts", "1"),
 				),
 			},
 		},
 	})
 }
 
-func TestDataSource_Provisioner(t *testing.T) {
-	t.Parallel()
+func TestDataSource_RetryAfter(t *testing.T) {
+	var requests int
 
 	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// A Content-Type that does not raise a warning in the Read function must be set in
-		// order to prevent test failure under TF 0.14.x as warnings result in no output
-		// being written which causes the local-exec command to fail with "Error:
-		// local-exec provisioner command must be a non-empty string".
-		// See https://github.com/hashicorp/terraform-provider-http/pull/74
+		requests++
 		w.Header().Set("Content-Type", "text/plain")
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
 		w.WriteHeader(http.StatusOK)
 	}))
 	defer svr.Close()
 
-	resource.Test(t, resource.TestCase{
+	resource.UnitTest(t, resource.TestCase{
 		ProtoV5ProviderFactories: protoV5ProviderFactories(),
-		ExternalProviders: map[string]resource.ExternalProvider{
-			"null": {
-				VersionConstraint: "3.1.1",
-				Source:            "hashicorp/null",
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() { requests = 0 },
+				// The minimum delay would exceed the test timeout if the
+				// Retry-After header was not honoured.
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url = "%s"
+
+								retry {
+									attempts     = 1
+									min_delay_ms = 600000
+									max_delay_ms = 600000
+								}
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "request_attempts", "2"),
+				),
 			},
 		},
+	})
+}
+
+func TestDataSource_RetryTransportError(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	// Closing the server up front results in the connection being refused.
+	svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
 		Steps: []resource.TestStep{
 			{
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
 								url = "%s"
-							}
-							resource "null_resource" "example" {
-  								provisioner "local-exec" {
-    								command = contains([201, 204], data.http.http_test.status_code)
-  								}
+
+								retry {
+									attempts     = 2
+									min_delay_ms = 1
+									max_delay_ms = 10
+								}
 							}`, svr.URL),
-				ExpectError: regexp.MustCompile(`Error running command 'false': exit status 1. Output:`),
+				ExpectError: regexp.MustCompile(`connection refused \(giving up after 3 attempts\)`),
 			},
 			{
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
 								url = "%s"
-							}
-							resource "null_resource" "example" {
-  								provisioner "local-exec" {
-    								command = contains([200], data.http.http_test.status_code)
-  								}
+
+								retry {
+									attempts         = 2
+									min_delay_ms     = 1
+									max_delay_ms     = 10
+									transport_errors = ["timeout"]
+								}
 							}`, svr.URL),
-				Check: resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				ExpectError: regexp.MustCompile(`connection refused\n`),
 			},
 		},
 	})
 }
 
-func TestDataSource_POST_201(t *testing.T) {
-	testHttpMock := setUpMockHttpServer(false)
+func TestDataSource_RetryInvalidDelay(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+							data "http" "http_test" {
+								url = "http://localhost"
 
-	defer testHttpMock.server.Close()
+								retry {
+									attempts     = 1
+									min_delay_ms = 100
+									max_delay_ms = 10
+								}
+							}`,
+				ExpectError: regexp.MustCompile(`Attribute retry.max_delay_ms value must be at least sum of`),
+			},
+		},
+	})
+}
+
+func TestDataSource_RequestTimeout(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte("1.0.0"))
+	}))
+	defer svr.Close()
 
 	resource.UnitTest(t, resource.TestCase{
 		ProtoV5ProviderFactories: protoV5ProviderFactories(),
@@ -292,22 +2704,37 @@ func TestDataSource_POST_201(t *testing.T) {
 			{
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
- 								url = "%s/create"
-								method = "POST" 
-							}`, testHttpMock.server.URL),
+								url = "%s"
+
+								request_timeout_ms = 50
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile(`The request timed out while reading the response body`),
+			},
+			{
+				Config: fmt.Sprintf(`
+							provider "http" {
+								request_timeout_ms = 50
+							}
+
+							data "http" "http_test" {
+								url = "%s"
+
+								request_timeout_ms = 5000
+							}`, svr.URL),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "created"),
-					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "201"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "1.0.0"),
 				),
 			},
 		},
 	})
 }
 
-func TestDataSource_HEAD_204(t *testing.T) {
-	testHttpMock := setUpMockHttpServer(false)
-
-	defer testHttpMock.server.Close()
+func TestDataSource_ResponseHeaderTimeout(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer svr.Close()
 
 	resource.UnitTest(t, resource.TestCase{
 		ProtoV5ProviderFactories: protoV5ProviderFactories(),
@@ -315,25 +2742,34 @@ func TestDataSource_HEAD_204(t *testing.T) {
 			{
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
- 								url = "%s/head"
-								method = "HEAD" 
-							}`, testHttpMock.server.URL),
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.Content-Type", "text/plain"),
-					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Single", "foobar"),
-					resource.TestCheckResourceAttr("data.http.http_test", "response_headers.X-Double", "1, 2"),
-					resource.TestCheckResourceAttr("data.http.http_test", "response_body", ""),
-					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
-				),
+								url = "%s"
+
+								response_header_timeout_ms = 50
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile(`The request timed out while waiting for the response headers`),
 			},
 		},
 	})
 }
 
-func TestDataSource_UnsupportedMethod(t *testing.T) {
-	testHttpMock := setUpMockHttpServer(false)
-
-	defer testHttpMock.server.Close()
+func TestDataSource_TLSHandshakeTimeout(t *testing.T) {
+	// The listener accepts connections but never responds, so the TLS
+	// handshake cannot complete.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error creating listener: %s", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
 
 	resource.UnitTest(t, resource.TestCase{
 		ProtoV5ProviderFactories: protoV5ProviderFactories(),
@@ -341,18 +2777,30 @@ func TestDataSource_UnsupportedMethod(t *testing.T) {
 			{
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
- 								url = "%s/200"
-								method = "OPTIONS" 
-							}`, testHttpMock.server.URL),
-				ExpectError: regexp.MustCompile(`.*value must be one of: \["\\"GET\\"" "\\"POST\\"" "\\"HEAD\\""`),
+								url = "https://%s"
+
+								tls_handshake_timeout_ms = 50
+							}`, listener.Addr()),
+				ExpectError: regexp.MustCompile(`The request timed out while performing the TLS handshake`),
 			},
 		},
 	})
 }
 
-func TestDataSource_WithCACertificate(t *testing.T) {
-	testHttpMock := setUpMockHttpServer(true)
-	defer testHttpMock.server.Close()
+func TestDataSource_ClientCertificate(t *testing.T) {
+	certPEM, key := testClientCertificate(t, "terraform-provider-http-client")
+	keyPEM := testKeyToPEM(t, key)
+
+	svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	svr.TLS = &tls.Config{
+		ClientAuth: tls.RequireAnyClientCert,
+	}
+	svr.StartTLS()
+	defer svr.Close()
 
 	resource.UnitTest(t, resource.TestCase{
 		ProtoV5ProviderFactories: protoV5ProviderFactories(),
@@ -360,43 +2808,47 @@ func TestDataSource_WithCACertificate(t *testing.T) {
 			{
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
-  								url = "%s/200"
+								url      = "%s"
+								insecure = true
 
-  								ca_cert_pem = <<EOF
+								client_cert_pem = <<EOF
 %s
 EOF
-							}`, testHttpMock.server.URL, CertToPEM(testHttpMock.server.Certificate())),
+								client_key_pem = <<EOF
+%s
+EOF
+							}`, svr.URL, certPEM, keyPEM),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "terraform-provider-http-client"),
 				),
 			},
-		},
-	})
-}
-
-func TestDataSource_WithCACertificateFalse(t *testing.T) {
-	testHttpMock := setUpMockHttpServer(true)
-	defer testHttpMock.server.Close()
-
-	resource.UnitTest(t, resource.TestCase{
-		ProtoV5ProviderFactories: protoV5ProviderFactories(),
-		Steps: []resource.TestStep{
 			{
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
-  								url = "%s/200"
+								url      = "%s"
+								insecure = true
 
-  								ca_cert_pem = "invalid"
-							}`, testHttpMock.server.URL),
-				ExpectError: regexp.MustCompile(`Can't add the CA certificate to certificate pool. Only PEM encoded\ncertificates are supported.`),
+								client_pkcs12_base64 = "%s"
+								client_key_password  = "test"
+							}`, svr.URL, testPKCS12Bundle),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "terraform-provider-http-client"),
+				),
 			},
 		},
 	})
 }
 
-func TestDataSource_InsecureTrue(t *testing.T) {
-	testHttpMock := setUpMockHttpServer(true)
-	defer testHttpMock.server.Close()
+func TestDataSource_TLSOptions(t *testing.T) {
+	svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, "%s %s %s %s", r.Proto, tlsVersionName(r.TLS.Version),
+			tls.CipherSuiteName(r.TLS.CipherSuite), r.TLS.ServerName)
+	}))
+	svr.EnableHTTP2 = true
+	svr.StartTLS()
+	defer svr.Close()
 
 	resource.UnitTest(t, resource.TestCase{
 		ProtoV5ProviderFactories: protoV5ProviderFactories(),
@@ -404,59 +2856,64 @@ func TestDataSource_InsecureTrue(t *testing.T) {
 			{
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
-  								url = "%s/200"
+								url             = "%s"
+								tls_server_name = "example.com"
 
-  								insecure = true
-							}`, testHttpMock.server.URL),
+								ca_cert_pem = <<EOF
+%s
+EOF
+							}`, svr.URL, CertToPEM(svr.Certificate())),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "HTTP/2.0 1.3 TLS_AES_128_GCM_SHA256 example.com"),
 				),
 			},
-		},
-	})
-}
-
-func TestDataSource_InsecureFalse(t *testing.T) {
-	testHttpMock := setUpMockHttpServer(true)
-	defer testHttpMock.server.Close()
-
-	resource.UnitTest(t, resource.TestCase{
-		ProtoV5ProviderFactories: protoV5ProviderFactories(),
-		Steps: []resource.TestStep{
 			{
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
-  								url = "%s/200"
-
-  								insecure = false
-							}`, testHttpMock.server.URL),
-				ExpectError: regexp.MustCompile(fmt.Sprintf(`Error making request: Get "%s/200": x509: `, testHttpMock.server.URL)),
+								url                = "%s"
+								insecure           = true
+								tls_max_version    = "1.2"
+								tls_cipher_suites  = ["TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"]
+								tls_alpn_protocols = ["http/1.1"]
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "HTTP/1.1 1.2 TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384 "),
+				),
 			},
-		},
-	})
-}
-
-func TestDataSource_InsecureUnconfigured(t *testing.T) {
-	testHttpMock := setUpMockHttpServer(true)
-	defer testHttpMock.server.Close()
-
-	resource.UnitTest(t, resource.TestCase{
-		ProtoV5ProviderFactories: protoV5ProviderFactories(),
-		Steps: []resource.TestStep{
 			{
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
-  								url = "%s/200"
-							}`, testHttpMock.server.URL),
-				ExpectError: regexp.MustCompile(fmt.Sprintf(`Error making request: Get "%s/200": x509: `, testHttpMock.server.URL)),
+								url             = "%s"
+								insecure        = true
+								tls_min_version = "1.3"
+								tls_max_version = "1.2"
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile("Invalid TLS version range"),
+			},
+			{
+				// The default minimum version is higher than the maximum one.
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url             = "%s"
+								insecure        = true
+								tls_max_version = "1.1"
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile("Invalid TLS version range"),
 			},
 		},
 	})
 }
 
-func TestDataSource_UnsupportedInsecureCaCert(t *testing.T) {
-	testHttpMock := setUpMockHttpServer(true)
-	defer testHttpMock.server.Close()
+func TestDataSource_TLSMinVersion(t *testing.T) {
+	svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+	}))
+	svr.TLS = &tls.Config{
+		MaxVersion: tls.VersionTLS12,
+	}
+	svr.StartTLS()
+	defer svr.Close()
 
 	resource.UnitTest(t, resource.TestCase{
 		ProtoV5ProviderFactories: protoV5ProviderFactories(),
@@ -464,70 +2921,58 @@ func TestDataSource_UnsupportedInsecureCaCert(t *testing.T) {
 			{
 				Config: fmt.Sprintf(`
 							data "http" "http_test" {
- 								url = "%s/200"
-								insecure = true
-								ca_cert_pem = "invalid"
-							}`, testHttpMock.server.URL),
-				ExpectError: regexp.MustCompile(`Attribute "insecure" cannot be specified when "ca_cert_pem" is specified`),
+								url             = "%s"
+								insecure        = true
+								tls_min_version = "1.2"
+							}`, svr.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							data "http" "http_test" {
+								url             = "%s"
+								insecure        = true
+								tls_min_version = "1.3"
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile("protocol version not supported"),
 			},
 		},
 	})
 }
 
-// testProxiedURL is a hardcoded URL used in acceptance testing where it is
-// expected that a locally started HTTP proxy will handle the request.
-//
-// Neither localhost nor the loopback interface (127.0.0.1) can be used for the
-// address of the server as httpproxy/proxy.go will ignore these addresses.
-//
-// References:
-//   - https://cs.opensource.google/go/x/net/+/internal-branch.go1.19-vendor:http/httpproxy/proxy.go;l=181
-//   - https://cs.opensource.google/go/x/net/+/internal-branch.go1.19-vendor:http/httpproxy/proxy.go;l=186
-const testProxiedURL = "http://terraform-provider-http-test-proxy"
-
-func TestDataSource_HTTPViaProxyWithEnv(t *testing.T) {
-	proxyRequests := 0
-	serverRequests := 0
-
-	// Content-Type is set to text/plain otherwise the http data source issues a warning which
-	// causes Terraform 0.14 to not write any data to state.
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		serverRequests++
-		w.Header().Set("Content-Type", "text/plain")
-		w.WriteHeader(http.StatusOK)
-	}))
-
-	defer server.Close()
-
-	serverURL, err := url.Parse(server.URL)
-
-	if err != nil {
-		t.Fatalf("error parsing server URL: %s", err)
+// tlsVersionName returns the tls_min_version value of a TLS version.
+func tlsVersionName(version uint16) string {
+	for name, v := range tlsVersions {
+		if v == version {
+			return name
+		}
 	}
 
-	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		proxyRequests++
-		httputil.NewSingleHostReverseProxy(serverURL).ServeHTTP(w, r)
-	}))
-	defer proxy.Close()
+	return fmt.Sprintf("0x%04x", version)
+}
 
-	t.Setenv("HTTP_PROXY", proxy.URL)
-	t.Setenv("HTTPS_PROXY", proxy.URL)
+func TestDataSource_ClientCertificateKeyMismatch(t *testing.T) {
+	certPEM, _ := testClientCertificate(t, "client")
+	_, otherKey := testClientCertificate(t, "other")
 
 	resource.UnitTest(t, resource.TestCase{
 		ProtoV5ProviderFactories: protoV5ProviderFactories(),
-
 		Steps: []resource.TestStep{
 			{
 				Config: fmt.Sprintf(`
-					data "http" "http_test" {
-						url = "%s"
-					}
-				`, testProxiedURL),
-				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
-					CheckServerAndProxyRequestCount(&proxyRequests, &serverRequests),
-				),
+							data "http" "http_test" {
+								url = "https://localhost"
+
+								client_cert_pem = <<EOF
+%s
+EOF
+								client_key_pem = <<EOF
+%s
+EOF
+							}`, certPEM, testKeyToPEM(t, otherKey)),
+				ExpectError: regexp.MustCompile(`Client certificate and key do not match`),
 			},
 		},
 	})
@@ -593,7 +3038,11 @@ func httpReqHandler(w http.ResponseWriter, r *http.Request) {
 	case "/utf-16/200":
 		w.Header().Set("Content-Type", "application/json; charset=UTF-16")
 		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("1.0.0"))
+		_, _ = w.Write([]byte("\xfe\xff\x00\"\x001\x00.\x000\x00.\x000\x00\""))
+	case "/iso-8859-1/200":
+		w.Header().Set("Content-Type", "text/plain; charset=ISO-8859-1")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("caf\xe9"))
 	case "/x509-ca-cert/200":
 		w.Header().Set("Content-Type", "application/x-x509-ca-cert")
 		w.WriteHeader(http.StatusOK)
@@ -618,3 +3067,254 @@ func CertToPEM(cert *x509.Certificate) string {
 
 	return strings.Trim(certPem, "\n")
 }
+
+// startSOCKS5Proxy starts a SOCKS5 proxy which requires the given username and
+// password, and connects every request to the target address. The addresses
+// requested by clients are sent on the returned channel.
+func startSOCKS5Proxy(t *testing.T, username, password, target string) (string, <-chan string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error starting SOCKS5 proxy: %s", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	requested := make(chan string, 16)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				addr, err := socks5Handshake(conn, username, password)
+				if err != nil {
+					return
+				}
+
+				upstream, err := net.Dial("tcp", target)
+				if err != nil {
+					return
+				}
+				defer upstream.Close()
+
+				select {
+				case requested <- addr:
+				default:
+				}
+
+				// Reply with success and a zero IPv4 bound address.
+				if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+					return
+				}
+
+				go func() { _, _ = io.Copy(upstream, conn) }()
+				_, _ = io.Copy(conn, upstream)
+			}()
+		}
+	}()
+
+	return "127.0.0.1:" + strconv.Itoa(listener.Addr().(*net.TCPAddr).Port), requested
+}
+
+// socks5Handshake performs the server side of the SOCKS5 greeting, username
+// and password authentication and CONNECT request, returning the requested
+// address.
+// See https://datatracker.ietf.org/doc/html/rfc1928 and
+// https://datatracker.ietf.org/doc/html/rfc1929
+func socks5Handshake(conn net.Conn, username, password string) (string, error) {
+	read := func(n int) ([]byte, error) {
+		buf := make([]byte, n)
+		_, err := io.ReadFull(conn, buf)
+		return buf, err
+	}
+
+	header, err := read(2)
+	if err != nil {
+		return "", err
+	}
+	if _, err := read(int(header[1])); err != nil {
+		return "", err
+	}
+
+	// Select username and password authentication.
+	if _, err := conn.Write([]byte{5, 2}); err != nil {
+		return "", err
+	}
+
+	version, err := read(2)
+	if err != nil {
+		return "", err
+	}
+	user, err := read(int(version[1]))
+	if err != nil {
+		return "", err
+	}
+	passwordLength, err := read(1)
+	if err != nil {
+		return "", err
+	}
+	pass, err := read(int(passwordLength[0]))
+	if err != nil {
+		return "", err
+	}
+
+	if string(user) != username || string(pass) != password {
+		_, _ = conn.Write([]byte{1, 1})
+		return "", fmt.Errorf("invalid credentials")
+	}
+	if _, err := conn.Write([]byte{1, 0}); err != nil {
+		return "", err
+	}
+
+	request, err := read(4)
+	if err != nil {
+		return "", err
+	}
+
+	var host string
+	switch request[3] {
+	case 1:
+		ip, err := read(4)
+		if err != nil {
+			return "", err
+		}
+		host = net.IP(ip).String()
+	case 3:
+		length, err := read(1)
+		if err != nil {
+			return "", err
+		}
+		name, err := read(int(length[0]))
+		if err != nil {
+			return "", err
+		}
+		host = string(name)
+	default:
+		return "", fmt.Errorf("unsupported address type %d", request[3])
+	}
+
+	port, err := read(2)
+	if err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(port[0])<<8|int(port[1]))), nil
+}
+
+// startDNSServer starts a UDP DNS server answering A queries for the given
+// host names, returning its address.
+func startDNSServer(t *testing.T, records map[string]string) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error starting DNS server: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			var request dnsmessage.Message
+			if err := request.Unpack(buf[:n]); err != nil || len(request.Questions) != 1 {
+				continue
+			}
+
+			question := request.Questions[0]
+			response := dnsmessage.Message{
+				Header: dnsmessage.Header{
+					ID:            request.ID,
+					Response:      true,
+					Authoritative: true,
+				},
+				Questions: request.Questions,
+			}
+
+			ip, ok := records[strings.TrimSuffix(question.Name.String(), ".")]
+			switch {
+			case !ok:
+				response.RCode = dnsmessage.RCodeNameError
+			case question.Type == dnsmessage.TypeA:
+				var a dnsmessage.AResource
+				copy(a.A[:], net.ParseIP(ip).To4())
+				response.Answers = []dnsmessage.Resource{{
+					Header: dnsmessage.ResourceHeader{
+						Name:  question.Name,
+						Type:  dnsmessage.TypeA,
+						Class: dnsmessage.ClassINET,
+						TTL:   60,
+					},
+					Body: &a,
+				}}
+			}
+
+			packed, err := response.Pack()
+			if err != nil {
+				continue
+			}
+
+			_, _ = conn.WriteTo(packed, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+// newTestMixedServer starts a server answering both HTTPS and HTTP requests on
+// the same port, so that a redirect from https to http keeps the host.
+func newTestMixedServer(handler http.Handler) *httptest.Server {
+	// The TLS configuration, with the certificate of httptest, is taken from
+	// a TLS server which is not used otherwise.
+	tlsSvr := httptest.NewTLSServer(handler)
+	tlsSvr.Close()
+
+	svr := httptest.NewUnstartedServer(handler)
+	svr.Listener = &mixedListener{Listener: svr.Listener, config: tlsSvr.TLS}
+	svr.Start()
+
+	return svr
+}
+
+// mixedListener accepts TLS connections, which start with a handshake record,
+// alongside plain connections.
+type mixedListener struct {
+	net.Listener
+	config *tls.Config
+}
+
+func (l *mixedListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	first, err := reader.Peek(1)
+	peeked := &peekedConn{Conn: conn, reader: reader}
+	if err == nil && first[0] == 0x16 {
+		return tls.Server(peeked, l.config), nil
+	}
+
+	return peeked, nil
+}
+
+// peekedConn reads through the reader used to peek at the connection.
+type peekedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *peekedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// The framework version in use has no dynamic attribute type, so one is
+// provided here. The values are passed through to Terraform as is, which
+// accepts any type for an attribute declared as dynamic.
+
+var (
+	_ attr.Type  = dynamicType{}
+	_ attr.Value = dynamicValue{}
+)
+
+// dynamicType is an attribute type whose values may be of any type.
+type dynamicType struct{}
+
+func (t dynamicType) TerraformType(_ context.Context) tftypes.Type {
+	return tftypes.DynamicPseudoType
+}
+
+func (t dynamicType) ValueFromTerraform(_ context.Context, value tftypes.Value) (attr.Value, error) {
+	return dynamicValue{value: value}, nil
+}
+
+func (t dynamicType) ValueType(_ context.Context) attr.Value {
+	return dynamicValue{}
+}
+
+func (t dynamicType) Equal(o attr.Type) bool {
+	_, ok := o.(dynamicType)
+
+	return ok
+}
+
+func (t dynamicType) String() string {
+	return "dynamicType"
+}
+
+func (t dynamicType) ApplyTerraform5AttributePathStep(step tftypes.AttributePathStep) (interface{}, error) {
+	return nil, fmt.Errorf("cannot apply AttributePathStep %T to %s", step, t.String())
+}
+
+// dynamicValue holds a value of any type. The zero value is null.
+type dynamicValue struct {
+	value tftypes.Value
+}
+
+func dynamicNull() dynamicValue {
+	return dynamicValue{
+		value: tftypes.NewValue(tftypes.DynamicPseudoType, nil),
+	}
+}
+
+func (v dynamicValue) Type(_ context.Context) attr.Type {
+	return dynamicType{}
+}
+
+func (v dynamicValue) ToTerraformValue(_ context.Context) (tftypes.Value, error) {
+	if v.value.Type() == nil {
+		return dynamicNull().value, nil
+	}
+
+	return v.value, nil
+}
+
+func (v dynamicValue) Equal(o attr.Value) bool {
+	other, ok := o.(dynamicValue)
+
+	return ok && v.value.Equal(other.value)
+}
+
+func (v dynamicValue) IsNull() bool {
+	return v.value.Type() == nil || v.value.IsNull()
+}
+
+func (v dynamicValue) IsUnknown() bool {
+	return v.value.Type() != nil && !v.value.IsKnown()
+}
+
+func (v dynamicValue) String() string {
+	if v.IsNull() {
+		return attr.NullValueString
+	}
+
+	if v.IsUnknown() {
+		return attr.UnknownValueString
+	}
+
+	return v.value.String()
+}
+
+// dynamicAttribute is a computed attribute of dynamicType. It reuses
+// StringAttribute for everything but its type.
+type dynamicAttribute struct {
+	schema.StringAttribute
+}
+
+func (a dynamicAttribute) GetType() attr.Type {
+	return dynamicType{}
+}
+
+// StringValidators shadows the method of the embedded StringAttribute, so
+// that the framework does not attempt to validate the value as a string.
+func (a dynamicAttribute) StringValidators() {}
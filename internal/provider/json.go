@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"mime"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// isContentTypeJSON reports whether the media type is application/json or a
+// structured syntax suffix type such as application/problem+json.
+func isContentTypeJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// isResponseJSON reports whether the Content-Type header of a response
+// declares a JSON body.
+func isResponseJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return isContentTypeJSON(mediaType)
+}
+
+// decodeJSON decodes a JSON document into a value of the type matching the
+// document, in the same way as the jsondecode function of Terraform: objects
+// become object values, arrays become tuple values and numbers keep their
+// full precision.
+func decodeJSON(data []byte) (tftypes.Value, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	value, err := decodeJSONValue(decoder)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return tftypes.Value{}, errors.New("unexpected data after the top-level value")
+	}
+
+	return value, nil
+}
+
+func decodeJSONValue(decoder *json.Decoder) (tftypes.Value, error) {
+	token, err := decoder.Token()
+	if errors.Is(err, io.EOF) {
+		return tftypes.Value{}, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		switch token {
+		case '{':
+			return decodeJSONObject(decoder)
+		case '[':
+			return decodeJSONArray(decoder)
+		}
+		return tftypes.Value{}, fmt.Errorf("unexpected delimiter %q", token)
+	case json.Number:
+		number, _, err := big.ParseFloat(string(token), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return tftypes.Value{}, fmt.Errorf("invalid number %q: %w", token, err)
+		}
+		return tftypes.NewValue(tftypes.Number, number), nil
+	case string:
+		return tftypes.NewValue(tftypes.String, token), nil
+	case bool:
+		return tftypes.NewValue(tftypes.Bool, token), nil
+	case nil:
+		return tftypes.NewValue(tftypes.DynamicPseudoType, nil), nil
+	}
+
+	return tftypes.Value{}, fmt.Errorf("unexpected token %v", token)
+}
+
+func decodeJSONObject(decoder *json.Decoder) (tftypes.Value, error) {
+	attributeTypes := map[string]tftypes.Type{}
+	attributes := map[string]tftypes.Value{}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+
+		key, ok := token.(string)
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("unexpected object key %v", token)
+		}
+
+		value, err := decodeJSONValue(decoder)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+
+		attributeTypes[key] = value.Type()
+		attributes[key] = value
+	}
+
+	// Consume the closing delimiter.
+	if _, err := decoder.Token(); err != nil {
+		return tftypes.Value{}, err
+	}
+
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: attributeTypes}, attributes), nil
+}
+
+func decodeJSONArray(decoder *json.Decoder) (tftypes.Value, error) {
+	elementTypes := []tftypes.Type{}
+	elements := []tftypes.Value{}
+
+	for decoder.More() {
+		value, err := decodeJSONValue(decoder)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+
+		elementTypes = append(elementTypes, value.Type())
+		elements = append(elements, value)
+	}
+
+	// Consume the closing delimiter.
+	if _, err := decoder.Token(); err != nil {
+		return tftypes.Value{}, err
+	}
+
+	return tftypes.NewValue(tftypes.Tuple{ElementTypes: elementTypes}, elements), nil
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	testCases := map[string]struct {
+		data        string
+		expected    tftypes.Value
+		expectError bool
+	}{
+		"string": {
+			data:     `"a"`,
+			expected: tftypes.NewValue(tftypes.String, "a"),
+		},
+		"number": {
+			data:     `1.5`,
+			expected: tftypes.NewValue(tftypes.Number, big.NewFloat(1.5)),
+		},
+		"bool": {
+			data:     `true`,
+			expected: tftypes.NewValue(tftypes.Bool, true),
+		},
+		"null": {
+			data:     `null`,
+			expected: tftypes.NewValue(tftypes.DynamicPseudoType, nil),
+		},
+		"object": {
+			data: `{"a": "x", "b": [1, null], "c": {}}`,
+			expected: tftypes.NewValue(
+				tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+					"a": tftypes.String,
+					"b": tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.Number, tftypes.DynamicPseudoType}},
+					"c": tftypes.Object{AttributeTypes: map[string]tftypes.Type{}},
+				}},
+				map[string]tftypes.Value{
+					"a": tftypes.NewValue(tftypes.String, "x"),
+					"b": tftypes.NewValue(
+						tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.Number, tftypes.DynamicPseudoType}},
+						[]tftypes.Value{
+							tftypes.NewValue(tftypes.Number, big.NewFloat(1)),
+							tftypes.NewValue(tftypes.DynamicPseudoType, nil),
+						},
+					),
+					"c": tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{}}, map[string]tftypes.Value{}),
+				},
+			),
+		},
+		"empty-array": {
+			data:     `[]`,
+			expected: tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{}}, []tftypes.Value{}),
+		},
+		"invalid": {
+			data:        `{"a": }`,
+			expectError: true,
+		},
+		"truncated": {
+			data:        `{"a": [1,`,
+			expectError: true,
+		},
+		"trailing-data": {
+			data:        `{} {}`,
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := decodeJSON([]byte(testCase.data))
+
+			if testCase.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got %s", actual)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !actual.Equal(testCase.expected) {
+				t.Errorf("expected %s, got %s", testCase.expected, actual)
+			}
+		})
+	}
+}
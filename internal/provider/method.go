@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"net/http"
+	"strings"
+)
+
+// methodQuery is the QUERY method, a safe alternative to POST for requests
+// which carry a query in their body.
+// See https://datatracker.ietf.org/doc/draft-ietf-httpbis-safe-method-w-body/
+const methodQuery = "QUERY"
+
+// dataSourceMethods are the methods allowed in the http data source. They are
+// all intended for reading, even though POST is not a safe method.
+var dataSourceMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodPost,
+	methodQuery,
+}
+
+// isMethodSafe reports whether the method is defined as safe, meaning that it
+// is not expected to change the state of the server.
+// See https://datatracker.ietf.org/doc/html/rfc9110#section-9.2.1
+func isMethodSafe(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, methodQuery:
+		return true
+	}
+
+	return false
+}
+
+// parseAllowHeader returns the methods listed in the Allow headers of the
+// response, in order and without duplicates, or nil if there is no Allow
+// header.
+func parseAllowHeader(header http.Header) []string {
+	values := header.Values("Allow")
+	if values == nil {
+		return nil
+	}
+
+	methods := []string{}
+	seen := map[string]bool{}
+	for _, value := range values {
+		for _, method := range strings.Split(value, ",") {
+			method = strings.TrimSpace(method)
+			if method == "" || seen[method] {
+				continue
+			}
+
+			seen[method] = true
+			methods = append(methods, method)
+		}
+	}
+
+	return methods
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseAllowHeader(t *testing.T) {
+	testCases := map[string]struct {
+		values   []string
+		expected []string
+	}{
+		"none": {
+			expected: nil,
+		},
+		"empty": {
+			values:   []string{""},
+			expected: []string{},
+		},
+		"single": {
+			values:   []string{"GET, HEAD,OPTIONS"},
+			expected: []string{"GET", "HEAD", "OPTIONS"},
+		},
+		"multiple": {
+			values:   []string{"GET, HEAD", "POST, GET", " , QUERY"},
+			expected: []string{"GET", "HEAD", "POST", "QUERY"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			header := http.Header{}
+			for _, value := range testCase.values {
+				header.Add("Allow", value)
+			}
+
+			actual := parseAllowHeader(header)
+
+			if !reflect.DeepEqual(actual, testCase.expected) {
+				t.Errorf("expected %#v, got %#v", testCase.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// oauth2ExpiryDelta is how long before its expiry a token is refreshed, so
+// that it does not expire while a request is in flight.
+const oauth2ExpiryDelta = 10 * time.Second
+
+type oauth2ClientCredentialsModel struct {
+	TokenURL     types.String `tfsdk:"token_url"`
+	ClientID     types.String `tfsdk:"client_id"`
+	ClientSecret types.String `tfsdk:"client_secret"`
+	Scopes       types.List   `tfsdk:"scopes"`
+	Audience     types.String `tfsdk:"audience"`
+	ExtraParams  types.Map    `tfsdk:"extra_params"`
+}
+
+// oauth2ClientCredentials is the configuration of the OAuth 2.0 client
+// credentials grant.
+// See https://datatracker.ietf.org/doc/html/rfc6749#section-4.4
+type oauth2ClientCredentials struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	params       url.Values
+}
+
+// key identifies the configuration in the token cache. It is hashed so that
+// the keys of the cache do not hold the client secret.
+func (c *oauth2ClientCredentials) key() string {
+	hash := sha256.New()
+	for _, part := range []string{c.tokenURL, c.clientID, c.clientSecret, c.params.Encode()} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// oauth2Token is an access token obtained from the token endpoint.
+type oauth2Token struct {
+	accessToken string
+	expiry      time.Time
+}
+
+// valid reports whether the token can still be used. A token without an
+// expiry never expires.
+func (t *oauth2Token) valid() bool {
+	return t != nil && (t.expiry.IsZero() || time.Now().Add(oauth2ExpiryDelta).Before(t.expiry))
+}
+
+// oauth2TokenCache holds the tokens obtained by a provider instance, so that
+// data sources using the same client credentials share a token until it
+// expires.
+type oauth2TokenCache struct {
+	mu      sync.Mutex
+	entries map[string]*oauth2TokenCacheEntry
+}
+
+type oauth2TokenCacheEntry struct {
+	mu    sync.Mutex
+	token *oauth2Token
+}
+
+func newOAuth2TokenCache() *oauth2TokenCache {
+	return &oauth2TokenCache{
+		entries: map[string]*oauth2TokenCacheEntry{},
+	}
+}
+
+// token returns a valid token for the configuration, requesting a new one
+// from the token endpoint when there is none or it has expired. Concurrent
+// callers with the same configuration wait for a single token request.
+func (c *oauth2TokenCache) token(ctx context.Context, client *http.Client, config *oauth2ClientCredentials) (*oauth2Token, error) {
+	key := config.key()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &oauth2TokenCacheEntry{}
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.token.valid() {
+		return entry.token, nil
+	}
+
+	token, err := requestOAuth2Token(ctx, client, config)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.token = token
+
+	return token, nil
+}
+
+// requestOAuth2Token requests an access token from the token endpoint. The
+// client credentials are sent using HTTP Basic authentication, and in the
+// request body if the token endpoint rejects them.
+func requestOAuth2Token(ctx context.Context, client *http.Client, config *oauth2ClientCredentials) (*oauth2Token, error) {
+	token, err := doOAuth2TokenRequest(ctx, client, config, true)
+	var tokenErr *oauth2TokenError
+	if errors.As(err, &tokenErr) {
+		token, err = doOAuth2TokenRequest(ctx, client, config, false)
+	}
+
+	return token, err
+}
+
+// oauth2TokenError is returned when the token endpoint responds with an
+// error.
+type oauth2TokenError struct {
+	status      string
+	code        string
+	description string
+}
+
+func (e *oauth2TokenError) Error() string {
+	if e.code == "" {
+		return fmt.Sprintf("token endpoint responded with %q", e.status)
+	}
+
+	if e.description == "" {
+		return fmt.Sprintf("token endpoint responded with %q: %s", e.status, e.code)
+	}
+
+	return fmt.Sprintf("token endpoint responded with %q: %s: %s", e.status, e.code, e.description)
+}
+
+func doOAuth2TokenRequest(ctx context.Context, client *http.Client, config *oauth2ClientCredentials, basicAuth bool) (*oauth2Token, error) {
+	params := url.Values{}
+	for name, values := range config.params {
+		params[name] = values
+	}
+	params.Set("grant_type", "client_credentials")
+
+	if !basicAuth {
+		params.Set("client_id", config.clientID)
+		params.Set("client_secret", config.clientSecret)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, config.tokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	if basicAuth {
+		// See https://datatracker.ietf.org/doc/html/rfc6749#section-2.3.1
+		request.SetBasicAuth(url.QueryEscape(config.clientID), url.QueryEscape(config.clientSecret))
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading token response: %w", err)
+	}
+
+	var result struct {
+		AccessToken      string          `json:"access_token"`
+		ExpiresIn        json.RawMessage `json:"expires_in"`
+		Error            string          `json:"error"`
+		ErrorDescription string          `json:"error_description"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil && response.StatusCode/100 == 2 {
+		return nil, fmt.Errorf("decoding token response: %w", err)
+	}
+
+	if response.StatusCode/100 != 2 {
+		return nil, &oauth2TokenError{
+			status:      response.Status,
+			code:        result.Error,
+			description: result.ErrorDescription,
+		}
+	}
+
+	if result.AccessToken == "" {
+		return nil, errors.New("token response does not contain an access_token")
+	}
+
+	token := &oauth2Token{
+		accessToken: result.AccessToken,
+	}
+
+	// Some token endpoints return expires_in as a string.
+	if expiresIn, err := strconv.ParseInt(strings.Trim(string(result.ExpiresIn), `"`), 10, 64); err == nil && expiresIn > 0 {
+		token.expiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+// newOAuth2ClientCredentials builds the client credentials configuration from
+// the oauth2_client_credentials block.
+func newOAuth2ClientCredentials(ctx context.Context, model *oauth2ClientCredentialsModel) (*oauth2ClientCredentials, error) {
+	config := &oauth2ClientCredentials{
+		tokenURL:     model.TokenURL.ValueString(),
+		clientID:     model.ClientID.ValueString(),
+		clientSecret: model.ClientSecret.ValueString(),
+		params:       url.Values{},
+	}
+
+	if !model.ExtraParams.IsNull() {
+		var extraParams map[string]string
+		if diags := model.ExtraParams.ElementsAs(ctx, &extraParams, false); diags.HasError() {
+			return nil, errors.New("reading extra_params")
+		}
+
+		for name, value := range extraParams {
+			config.params.Set(name, value)
+		}
+	}
+
+	if !model.Scopes.IsNull() {
+		var scopes []string
+		if diags := model.Scopes.ElementsAs(ctx, &scopes, false); diags.HasError() {
+			return nil, errors.New("reading scopes")
+		}
+
+		config.params.Set("scope", strings.Join(scopes, " "))
+	}
+
+	if !model.Audience.IsNull() {
+		config.params.Set("audience", model.Audience.ValueString())
+	}
+
+	return config, nil
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestOAuth2TokenCache(t *testing.T) {
+	var requests []url.Values
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		requests = append(requests, r.PostForm)
+
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok {
+			clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+		}
+
+		switch {
+		case clientID == "post" && ok:
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": "invalid_client"}`))
+		case clientSecret != "secret":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_client", "error_description": "bad secret"}`))
+		case r.PostForm.Get("scope") == "short":
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"access_token": "token-%d", "token_type": "bearer", "expires_in": "5"}`, len(requests))))
+		default:
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"access_token": "token-%d", "token_type": "bearer", "expires_in": 3600}`, len(requests))))
+		}
+	}))
+	defer svr.Close()
+
+	testCases := map[string]struct {
+		config           oauth2ClientCredentials
+		expectedTokens   []string
+		expectedRequests int
+		expectedError    string
+	}{
+		"cached": {
+			config: oauth2ClientCredentials{
+				tokenURL:     svr.URL,
+				clientID:     "basic",
+				clientSecret: "secret",
+				params:       url.Values{"audience": {"api"}},
+			},
+			expectedTokens:   []string{"token-1", "token-1"},
+			expectedRequests: 1,
+		},
+		"expired": {
+			config: oauth2ClientCredentials{
+				tokenURL:     svr.URL,
+				clientID:     "basic",
+				clientSecret: "secret",
+				params:       url.Values{"scope": {"short"}},
+			},
+			expectedTokens:   []string{"token-1", "token-2"},
+			expectedRequests: 2,
+		},
+		"client-secret-post": {
+			config: oauth2ClientCredentials{
+				tokenURL:     svr.URL,
+				clientID:     "post",
+				clientSecret: "secret",
+			},
+			expectedTokens:   []string{"token-2", "token-2"},
+			expectedRequests: 2,
+		},
+		"error": {
+			config: oauth2ClientCredentials{
+				tokenURL:     svr.URL,
+				clientID:     "basic",
+				clientSecret: "wrong",
+			},
+			expectedError:    `token endpoint responded with "400 Bad Request": invalid_client: bad secret`,
+			expectedRequests: 2,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			requests = nil
+			cache := newOAuth2TokenCache()
+
+			var tokens []string
+			for i := 0; i < 2; i++ {
+				token, err := cache.token(context.Background(), svr.Client(), &testCase.config)
+				if err != nil {
+					if err.Error() != testCase.expectedError {
+						t.Fatalf("expected error %q, got %q", testCase.expectedError, err)
+					}
+					break
+				}
+				tokens = append(tokens, token.accessToken)
+			}
+
+			if fmt.Sprint(tokens) != fmt.Sprint(testCase.expectedTokens) {
+				t.Errorf("expected tokens %v, got %v", testCase.expectedTokens, tokens)
+			}
+
+			if len(requests) != testCase.expectedRequests {
+				t.Errorf("expected %d token requests, got %d", testCase.expectedRequests, len(requests))
+			}
+
+			for _, form := range requests {
+				if form.Get("grant_type") != "client_credentials" {
+					t.Errorf("expected grant_type client_credentials, got %q", form.Get("grant_type"))
+				}
+			}
+		})
+	}
+}
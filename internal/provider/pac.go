@@ -0,0 +1,498 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+const (
+	// maxPACFileBytes limits the size of a PAC file.
+	maxPACFileBytes = 1024 * 1024
+
+	// pacTimeout limits the time taken to run the PAC file or a call of
+	// FindProxyForURL, which would otherwise block requests forever.
+	pacTimeout = 10 * time.Second
+)
+
+// pacResolver picks the proxy of each request by calling the
+// FindProxyForURL function of a proxy auto-config (PAC) file.
+// See https://developer.mozilla.org/en-US/docs/Web/HTTP/Proxy_servers_and_tunneling/Proxy_Auto-Configuration_PAC_file
+type pacResolver struct {
+	// pacURL is the URL of the PAC file, which is downloaded with client
+	// before the first request.
+	pacURL string
+	client *http.Client
+
+	// now returns the time used by the weekdayRange, dateRange and
+	// timeRange functions.
+	now func() time.Time
+
+	// mu guards the fields below, as a goja runtime must not be used
+	// concurrently.
+	mu        sync.Mutex
+	vm        *goja.Runtime
+	findProxy goja.Callable
+
+	// cache holds the proxy of each scheme and host, nil for a direct
+	// connection, for the lifetime of the provider.
+	cache map[string]*url.URL
+}
+
+// newPACResolver returns a resolver running the PAC file content, or the PAC
+// file downloaded from pacURL if content is empty.
+func newPACResolver(pacURL, content string, client *http.Client) (*pacResolver, error) {
+	r := &pacResolver{
+		pacURL: pacURL,
+		client: client,
+		now:    time.Now,
+		cache:  map[string]*url.URL{},
+	}
+
+	if content != "" {
+		if err := r.load(content); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// proxy implements http.Transport.Proxy.
+func (r *pacResolver) proxy(req *http.Request) (*url.URL, error) {
+	return r.proxyForURL(req.Context(), req.URL)
+}
+
+// proxyForURL returns the proxy for the URL, or nil if the request is sent
+// directly. FindProxyForURL is called with the scheme and host of the URL
+// only, as browsers do for https URLs, so that its result can be cached.
+func (r *pacResolver) proxyForURL(ctx context.Context, u *url.URL) (*url.URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := u.Scheme + "://" + strings.ToLower(u.Host)
+	if proxyURL, ok := r.cache[key]; ok {
+		return proxyURL, nil
+	}
+
+	if r.findProxy == nil {
+		content, err := r.fetch(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("downloading PAC file: %w", err)
+		}
+
+		if err := r.load(content); err != nil {
+			return nil, err
+		}
+	}
+
+	var result goja.Value
+	err := r.run(func() error {
+		var err error
+		result, err = r.findProxy(goja.Undefined(), r.vm.ToValue(key+"/"), r.vm.ToValue(u.Hostname()))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("calling FindProxyForURL: %w", err)
+	}
+
+	var resultString string
+	if !goja.IsUndefined(result) && !goja.IsNull(result) {
+		resultString = result.String()
+	}
+
+	proxyURL, err := parsePACResult(resultString)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache[key] = proxyURL
+
+	return proxyURL, nil
+}
+
+func (r *pacResolver) fetch(ctx context.Context) (string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, r.pacURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return "", fmt.Errorf("PAC file request responded with %q", response.Status)
+	}
+
+	data, _, err := readResponseBody(response, maxPACFileBytes, false)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// load runs the PAC file in a new runtime providing the PAC functions.
+func (r *pacResolver) load(content string) error {
+	program, err := goja.Compile("pac", content, false)
+	if err != nil {
+		return fmt.Errorf("parsing PAC file: %w", err)
+	}
+
+	vm := goja.New()
+	for name, fn := range r.pacFunctions(vm) {
+		if err := vm.Set(name, fn); err != nil {
+			return err
+		}
+	}
+
+	r.vm = vm
+	if err := r.run(func() error {
+		_, err := vm.RunProgram(program)
+		return err
+	}); err != nil {
+		r.vm = nil
+		return fmt.Errorf("running PAC file: %w", err)
+	}
+
+	findProxy, ok := goja.AssertFunction(vm.Get("FindProxyForURL"))
+	if !ok {
+		r.vm = nil
+		return errors.New("the PAC file does not define a FindProxyForURL function")
+	}
+
+	r.findProxy = findProxy
+
+	return nil
+}
+
+// run calls fn, interrupting the runtime once pacTimeout has elapsed.
+func (r *pacResolver) run(fn func() error) error {
+	timer := time.AfterFunc(pacTimeout, func() {
+		r.vm.Interrupt("timed out")
+	})
+	defer func() {
+		timer.Stop()
+		r.vm.ClearInterrupt()
+	}()
+
+	return fn()
+}
+
+// pacFunctions returns the functions available to PAC files.
+func (r *pacResolver) pacFunctions(vm *goja.Runtime) map[string]interface{} {
+	lookup := func(host string) net.IP {
+		if ip := net.ParseIP(host); ip != nil {
+			return ip
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), pacTimeout)
+		defer cancel()
+
+		ips, err := net.DefaultResolver.LookupIP(ctx, "ip4", host)
+		if err != nil || len(ips) == 0 {
+			return nil
+		}
+
+		return ips[0]
+	}
+
+	stringArgs := func(call goja.FunctionCall) ([]string, bool) {
+		args := make([]string, len(call.Arguments))
+		for i, arg := range call.Arguments {
+			args[i] = arg.String()
+		}
+
+		gmt := len(args) > 0 && strings.EqualFold(args[len(args)-1], "GMT")
+		if gmt {
+			args = args[:len(args)-1]
+		}
+
+		return args, gmt
+	}
+
+	now := func(gmt bool) time.Time {
+		if gmt {
+			return r.now().UTC()
+		}
+		return r.now().Local()
+	}
+
+	return map[string]interface{}{
+		"isPlainHostName": func(host string) bool {
+			return !strings.Contains(host, ".")
+		},
+		"dnsDomainIs": func(host, domain string) bool {
+			return strings.HasSuffix(strings.ToLower(host), strings.ToLower(domain))
+		},
+		"localHostOrDomainIs": func(host, hostdom string) bool {
+			host, hostdom = strings.ToLower(host), strings.ToLower(hostdom)
+			return host == hostdom || !strings.Contains(host, ".") && strings.HasPrefix(hostdom, host+".")
+		},
+		"isResolvable": func(host string) bool {
+			return lookup(host) != nil
+		},
+		"isInNet": func(host, pattern, mask string) bool {
+			ip := lookup(host).To4()
+			patternIP := net.ParseIP(pattern).To4()
+			maskIP := net.ParseIP(mask).To4()
+			if ip == nil || patternIP == nil || maskIP == nil {
+				return false
+			}
+
+			ipMask := net.IPMask(maskIP)
+			return ip.Mask(ipMask).Equal(patternIP.Mask(ipMask))
+		},
+		"dnsResolve": func(host string) goja.Value {
+			ip := lookup(host)
+			if ip == nil {
+				return goja.Null()
+			}
+			return vm.ToValue(ip.String())
+		},
+		"myIpAddress": func() string {
+			return myIPAddress()
+		},
+		"dnsDomainLevels": func(host string) int {
+			return strings.Count(host, ".")
+		},
+		"shExpMatch": func(str, shexp string) bool {
+			return shExpMatch(str, shexp)
+		},
+		"weekdayRange": func(call goja.FunctionCall) goja.Value {
+			args, gmt := stringArgs(call)
+			return vm.ToValue(weekdayRange(now(gmt), args))
+		},
+		"dateRange": func(call goja.FunctionCall) goja.Value {
+			args, gmt := stringArgs(call)
+			return vm.ToValue(dateRange(now(gmt), args))
+		},
+		"timeRange": func(call goja.FunctionCall) goja.Value {
+			args, gmt := stringArgs(call)
+			return vm.ToValue(timeRange(now(gmt), args))
+		},
+		"alert": func(string) {},
+	}
+}
+
+// parsePACResult returns the proxy for the first supported entry of a
+// FindProxyForURL result, such as "PROXY proxy.example.com:8080; DIRECT", or
+// nil for DIRECT. An empty result stands for DIRECT.
+func parsePACResult(result string) (*url.URL, error) {
+	if strings.TrimSpace(result) == "" {
+		return nil, nil
+	}
+
+	for _, entry := range strings.Split(result, ";") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var scheme string
+		switch strings.ToUpper(fields[0]) {
+		case "DIRECT":
+			return nil, nil
+		case "PROXY", "HTTP":
+			scheme = "http"
+		case "HTTPS":
+			scheme = "https"
+		case "SOCKS", "SOCKS5":
+			scheme = "socks5"
+		default:
+			continue
+		}
+
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid FindProxyForURL result %q", result)
+		}
+
+		return &url.URL{Scheme: scheme, Host: fields[1]}, nil
+	}
+
+	return nil, fmt.Errorf("FindProxyForURL result %q has no supported proxy", result)
+}
+
+// myIPAddress returns the first IPv4 address of the host which is not a
+// loopback address.
+func myIPAddress() string {
+	addrs, err := net.InterfaceAddrs()
+	if err == nil {
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+				return ipNet.IP.String()
+			}
+		}
+	}
+
+	return "127.0.0.1"
+}
+
+// shExpMatch reports whether str matches the shell expression shexp, in
+// which * matches any characters, including /, and ? any single character.
+func shExpMatch(str, shexp string) bool {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+
+	for _, c := range shexp {
+		switch c {
+		case '*':
+			pattern.WriteString(".*")
+		case '?':
+			pattern.WriteString(".")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	pattern.WriteString("$")
+
+	matched, err := regexp.MatchString(pattern.String(), str)
+	return err == nil && matched
+}
+
+var (
+	pacWeekdays = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+	pacMonths   = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+)
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if strings.EqualFold(v, value) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// inRange reports whether value is between start and end inclusive, wrapping
+// around when end is before start.
+func inRange(value, start, end int) bool {
+	if start <= end {
+		return start <= value && value <= end
+	}
+
+	return value >= start || value <= end
+}
+
+// weekdayRange implements weekdayRange(wd1[, wd2]).
+func weekdayRange(now time.Time, args []string) bool {
+	if len(args) == 0 || len(args) > 2 {
+		return false
+	}
+
+	start := indexOf(pacWeekdays, args[0])
+	end := start
+	if len(args) == 2 {
+		end = indexOf(pacWeekdays, args[1])
+	}
+
+	if start < 0 || end < 0 {
+		return false
+	}
+
+	return inRange(int(now.Weekday()), start, end)
+}
+
+// dateRange implements dateRange with one field or a range of one to three
+// fields, where numbers up to 31 are days, larger numbers years and names
+// months, such as dateRange(1, "JAN", 1995, 15, "JUN", 1995).
+func dateRange(now time.Time, args []string) bool {
+	if len(args) == 0 || len(args) > 6 || len(args) > 1 && len(args)%2 != 0 {
+		return false
+	}
+
+	type field struct {
+		kind  string
+		value int
+	}
+
+	fields := make([]field, len(args))
+	for i, arg := range args {
+		if month := indexOf(pacMonths, arg); month >= 0 {
+			fields[i] = field{kind: "month", value: month + 1}
+			continue
+		}
+
+		var n int
+		if _, err := fmt.Sscanf(arg, "%d", &n); err != nil {
+			return false
+		}
+
+		if n <= 31 {
+			fields[i] = field{kind: "day", value: n}
+		} else {
+			fields[i] = field{kind: "year", value: n}
+		}
+	}
+
+	current := map[string]int{
+		"year":  now.Year(),
+		"month": int(now.Month()),
+		"day":   now.Day(),
+	}
+
+	if len(fields) == 1 {
+		return current[fields[0].kind] == fields[0].value
+	}
+
+	// Dates are compared as numbers made of the year, month and day fields
+	// of the range, such as 19950115.
+	weights := map[string]int{"year": 10000, "month": 100, "day": 1}
+
+	start, end, value := 0, 0, 0
+	half := len(fields) / 2
+	for i := 0; i < half; i++ {
+		if fields[i].kind != fields[half+i].kind {
+			return false
+		}
+
+		weight := weights[fields[i].kind]
+		start += fields[i].value * weight
+		end += fields[half+i].value * weight
+		value += current[fields[i].kind] * weight
+	}
+
+	return inRange(value, start, end)
+}
+
+// timeRange implements timeRange(hour), timeRange(hour1, hour2),
+// timeRange(hour1, min1, hour2, min2) and
+// timeRange(hour1, min1, sec1, hour2, min2, sec2).
+func timeRange(now time.Time, args []string) bool {
+	values := make([]int, len(args))
+	for i, arg := range args {
+		if _, err := fmt.Sscanf(arg, "%d", &values[i]); err != nil {
+			return false
+		}
+	}
+
+	seconds := now.Hour()*3600 + now.Minute()*60 + now.Second()
+
+	switch len(values) {
+	case 1:
+		return now.Hour() == values[0]
+	case 2:
+		return inRange(seconds, values[0]*3600, values[1]*3600+3599)
+	case 4:
+		return inRange(seconds, values[0]*3600+values[1]*60, values[2]*3600+values[3]*60+59)
+	case 6:
+		return inRange(seconds, values[0]*3600+values[1]*60+values[2], values[3]*3600+values[4]*60+values[5])
+	}
+
+	return false
+}
@@ -0,0 +1,255 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParsePACResult(t *testing.T) {
+	testCases := map[string]struct {
+		result        string
+		expectedProxy string
+		expectedErr   bool
+	}{
+		"empty": {
+			result: "",
+		},
+		"direct": {
+			result: "DIRECT",
+		},
+		"proxy": {
+			result:        "PROXY proxy.example.com:8080; DIRECT",
+			expectedProxy: "http://proxy.example.com:8080",
+		},
+		"https": {
+			result:        "HTTPS proxy.example.com:443",
+			expectedProxy: "https://proxy.example.com:443",
+		},
+		"socks": {
+			result:        "SOCKS proxy.example.com:1080",
+			expectedProxy: "socks5://proxy.example.com:1080",
+		},
+		"unsupported-first": {
+			result:        "SOCKS4 proxy.example.com:1080; socks5 proxy.example.com:1081",
+			expectedProxy: "socks5://proxy.example.com:1081",
+		},
+		"direct-first": {
+			result: "DIRECT; PROXY proxy.example.com:8080",
+		},
+		"no-host": {
+			result:      "PROXY",
+			expectedErr: true,
+		},
+		"unsupported": {
+			result:      "SOCKS4 proxy.example.com:1080",
+			expectedErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			proxyURL, err := parsePACResult(testCase.result)
+			if testCase.expectedErr {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var proxy string
+			if proxyURL != nil {
+				proxy = proxyURL.String()
+			}
+
+			if proxy != testCase.expectedProxy {
+				t.Errorf("expected proxy %q, got %q", testCase.expectedProxy, proxy)
+			}
+		})
+	}
+}
+
+func TestPACResolver(t *testing.T) {
+	content := `
+		var calls = 0;
+
+		function FindProxyForURL(url, host) {
+			calls++;
+
+			if (isPlainHostName(host) || dnsDomainIs(host, ".internal.example.com") || isInNet(host, "10.0.0.0", "255.0.0.0")) {
+				return "DIRECT";
+			}
+
+			if (shExpMatch(url, "https://*.example.org/")) {
+				return "SOCKS5 socks.example.com:1080";
+			}
+
+			if (weekdayRange("SAT", "SUN") || !timeRange(8, 17)) {
+				return "PROXY night.example.com:3128";
+			}
+
+			return "PROXY proxy.example.com:3128; DIRECT";
+		}`
+
+	r, err := newPACResolver("", content, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// A Monday at noon.
+	r.now = func() time.Time {
+		return time.Date(2023, time.January, 2, 12, 0, 0, 0, time.Local)
+	}
+
+	testCases := map[string]string{
+		"http://intranet/":                  "",
+		"http://app.internal.example.com/a": "",
+		"http://10.1.2.3:8080/":             "",
+		"https://www.example.org/path":      "socks5://socks.example.com:1080",
+		"http://www.example.net/":           "http://proxy.example.com:3128",
+		"http://www.example.net/other":      "http://proxy.example.com:3128",
+	}
+
+	for rawURL, expectedProxy := range testCases {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		proxyURL, err := r.proxyForURL(context.Background(), u)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", rawURL, err)
+		}
+
+		var proxy string
+		if proxyURL != nil {
+			proxy = proxyURL.String()
+		}
+
+		if proxy != expectedProxy {
+			t.Errorf("expected proxy %q for %s, got %q", expectedProxy, rawURL, proxy)
+		}
+	}
+
+	// The result for http://www.example.net is cached.
+	if calls := r.vm.Get("calls").ToInteger(); calls != int64(len(testCases)-1) {
+		t.Errorf("expected %d FindProxyForURL calls, got %d", len(testCases)-1, calls)
+	}
+}
+
+func TestPACResolver_URL(t *testing.T) {
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/x-ns-proxy-autoconfig")
+		_, _ = w.Write([]byte(`function FindProxyForURL(url, host) { return "PROXY proxy.example.com:3128"; }`))
+	}))
+	defer server.Close()
+
+	r, err := newPACResolver(server.URL, "", server.Client())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, rawURL := range []string{"http://a.example.com/", "http://b.example.com/"} {
+		req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		proxyURL, err := r.proxy(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if proxyURL == nil || proxyURL.String() != "http://proxy.example.com:3128" {
+			t.Errorf("expected proxy http://proxy.example.com:3128, got %v", proxyURL)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("expected the PAC file to be downloaded once, got %d requests", requests)
+	}
+}
+
+func TestNewPACResolver_Invalid(t *testing.T) {
+	testCases := map[string]string{
+		"syntax":      `function FindProxyForURL(url, host) {`,
+		"no-function": `var x = 1;`,
+		"runtime":     `throw new Error("failed");`,
+	}
+
+	for name, content := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := newPACResolver("", content, nil); err == nil {
+				t.Fatal("expected error, got none")
+			}
+		})
+	}
+}
+
+func TestShExpMatch(t *testing.T) {
+	testCases := []struct {
+		str      string
+		shexp    string
+		expected bool
+	}{
+		{"http://home.netscape.com/people/ari/index.html", "*/ari/*", true},
+		{"http://home.netscape.com/people/montulli/index.html", "*/ari/*", false},
+		{"www.example.com", "*.example.com", true},
+		{"example.com", "*.example.com", false},
+		{"a.b", "a?b", true},
+		{"a+b", "a+b", true},
+	}
+
+	for _, testCase := range testCases {
+		if actual := shExpMatch(testCase.str, testCase.shexp); actual != testCase.expected {
+			t.Errorf("shExpMatch(%q, %q): expected %t, got %t", testCase.str, testCase.shexp, testCase.expected, actual)
+		}
+	}
+}
+
+func TestPACTimeFunctions(t *testing.T) {
+	// A Wednesday afternoon.
+	now := time.Date(1995, time.June, 14, 14, 30, 15, 0, time.UTC)
+
+	testCases := map[string]struct {
+		fn       func(time.Time, []string) bool
+		args     []string
+		expected bool
+	}{
+		"weekday":                {weekdayRange, []string{"WED"}, true},
+		"weekday-other":          {weekdayRange, []string{"MON"}, false},
+		"weekday-range":          {weekdayRange, []string{"MON", "FRI"}, true},
+		"weekday-range-wrap":     {weekdayRange, []string{"FRI", "MON"}, false},
+		"date-day":               {dateRange, []string{"14"}, true},
+		"date-month":             {dateRange, []string{"JUN"}, true},
+		"date-year":              {dateRange, []string{"1996"}, false},
+		"date-month-range":       {dateRange, []string{"MAY", "AUG"}, true},
+		"date-month-range-wrap":  {dateRange, []string{"DEC", "MAR"}, false},
+		"date-day-month-range":   {dateRange, []string{"1", "JUN", "15", "JUN"}, true},
+		"date-full-range":        {dateRange, []string{"1", "JAN", "1995", "13", "JUN", "1995"}, false},
+		"date-mixed-range":       {dateRange, []string{"1", "JUN"}, false},
+		"time-hour":              {timeRange, []string{"14"}, true},
+		"time-hour-range":        {timeRange, []string{"9", "14"}, true},
+		"time-hour-range-wrap":   {timeRange, []string{"22", "6"}, false},
+		"time-minute-range":      {timeRange, []string{"14", "0", "14", "29"}, false},
+		"time-second-range":      {timeRange, []string{"14", "30", "0", "14", "30", "15"}, true},
+		"time-invalid-arguments": {timeRange, []string{"1", "2", "3"}, false},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if actual := testCase.fn(now, testCase.args); actual != testCase.expected {
+				t.Errorf("expected %t, got %t", testCase.expected, actual)
+			}
+		})
+	}
+}
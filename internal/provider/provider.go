@@ -2,10 +2,24 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 func New() provider.Provider {
@@ -20,14 +34,232 @@ func (p *httpProvider) Metadata(_ context.Context, _ provider.MetadataRequest, r
 	resp.TypeName = "http"
 }
 
-func (p *httpProvider) Schema(context.Context, provider.SchemaRequest, *provider.SchemaResponse) {
+func (p *httpProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The provider configuration sets defaults which are shared by all `http` data sources " +
+			"and `http_request` resources. Values set on a data source take precedence over those set here.",
+
+		Attributes: map[string]schema.Attribute{
+			"base_url": schema.StringAttribute{
+				Description: "The URL against which relative data source URLs are resolved, " +
+					"according to [RFC 3986](https://datatracker.ietf.org/doc/html/rfc3986#section-5). " +
+					"Supported schemes are `http` and `https`.",
+				Optional: true,
+			},
+
+			"request_headers": schema.MapAttribute{
+				Description: "A map of request header field names and values sent with every request. " +
+					"Headers set on a data source are merged with these, replacing any header of the same name.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+
+			"ca_cert_pem": schema.StringAttribute{
+				Description: "Certificate data of the Certificate Authority (CA) " +
+					"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format, " +
+					"used when a data source does not set `ca_cert_pem`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("insecure")),
+				},
+			},
+
+			"insecure": schema.BoolAttribute{
+				Description: "Disables verification of the server's certificate chain and hostname, " +
+					"used when a data source sets neither `insecure` nor `ca_cert_pem`. Defaults to `false`",
+				Optional: true,
+			},
+
+			"request_timeout_ms": schema.Int64Attribute{
+				Description: "The request timeout in milliseconds, covering the whole exchange " +
+					"including reading the response body. Defaults to no timeout.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"cache_dir": schema.StringAttribute{
+				Description: "The directory in which responses are cached by data sources with `cache` set to " +
+					"`true`. Defaults to the `terraform-provider-http` directory in the user cache directory, " +
+					"such as `~/.cache/terraform-provider-http` on Linux.",
+				Optional: true,
+			},
+
+			"cache_ttl_ms": schema.Int64Attribute{
+				Description: "How long in milliseconds a cached response is used without a request. Once it " +
+					"is older, the response is revalidated with a conditional request. Defaults to `0`, " +
+					"which revalidates the response on every read.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+
+			"proxy_pac_url": schema.StringAttribute{
+				Description: "The URL of a [proxy auto-config (PAC) file](https://developer.mozilla.org/en-US/docs/Web/HTTP/Proxy_servers_and_tunneling/Proxy_Auto-Configuration_PAC_file), " +
+					"whose `FindProxyForURL` function picks the proxy of each request instead of the " +
+					"`HTTP_PROXY`, `HTTPS_PROXY` and `NO_PROXY` environment variables. The PAC file is " +
+					"downloaded directly, before the first request. Conflicts with `proxy_pac_content` and `proxy`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("proxy_pac_content"),
+						path.MatchRoot("proxy"),
+					),
+				},
+			},
+
+			"proxy_pac_content": schema.StringAttribute{
+				Description: "The content of a proxy auto-config (PAC) file, used in the same way as " +
+					"`proxy_pac_url`. `FindProxyForURL` is called with the scheme and host of the request " +
+					"URL only, and its result is cached for each scheme and host until the provider is " +
+					"reconfigured. The first `DIRECT`, `PROXY`, `HTTP`, `HTTPS`, `SOCKS` or `SOCKS5` " +
+					"entry of the result is used. Conflicts with `proxy_pac_url` and `proxy`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("proxy")),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"proxy": schema.SingleNestedBlock{
+				Description: "Proxy used for all requests. When not set, the proxy is taken from the " +
+					"`HTTP_PROXY`, `HTTPS_PROXY` and `NO_PROXY` environment variables. HTTP and HTTPS proxies " +
+					"are supported, using `CONNECT` for `https` URLs, as are SOCKS5 proxies.",
+				Validators: []validator.Object{
+					objectvalidator.AlsoRequires(path.MatchRelative().AtName("url")),
+				},
+				Attributes: map[string]schema.Attribute{
+					"url": schema.StringAttribute{
+						Description: "The URL of the proxy, which must be set, with one of the `http`, `https`, " +
+							"`socks5` and `socks5h` schemes. The host name of the request is resolved locally with " +
+							"`socks5` and by the proxy with `socks5h`.",
+						Optional: true,
+					},
+					"username": schema.StringAttribute{
+						Description: "The username used to authenticate with the proxy.",
+						Optional:    true,
+						Validators: []validator.String{
+							stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("url")),
+						},
+					},
+					"password": schema.StringAttribute{
+						Description: "The password used to authenticate with the proxy.",
+						Optional:    true,
+						Sensitive:   true,
+						Validators: []validator.String{
+							stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("username")),
+						},
+					},
+					"no_proxy": schema.ListAttribute{
+						Description: "Host names, domain suffixes, IP addresses or CIDR ranges which are " +
+							"requested directly, using the same syntax as the `NO_PROXY` environment variable.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+			},
+		},
+	}
 }
 
-func (p *httpProvider) Configure(context.Context, provider.ConfigureRequest, *provider.ConfigureResponse) {
+func (p *httpProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var config httpProviderModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	providerData := &httpProviderData{
+		requestHeaders: map[string]string{},
+		caCertificate:  config.CaCertificate,
+		insecure:       config.Insecure,
+		oauth2Tokens:   newOAuth2TokenCache(),
+	}
+
+	if !config.BaseURL.IsNull() {
+		baseURL, err := url.Parse(config.BaseURL.ValueString())
+		if err == nil && (baseURL.Scheme != "http" && baseURL.Scheme != "https" || baseURL.Host == "") {
+			err = fmt.Errorf("expected an absolute http or https URL, got %q", config.BaseURL.ValueString())
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("base_url"),
+				"Invalid base URL",
+				fmt.Sprintf("Error parsing base URL: %s", err),
+			)
+			return
+		}
+
+		providerData.baseURL = baseURL
+	}
+
+	diags = config.RequestHeaders.ElementsAs(ctx, &providerData.requestHeaders, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.RequestTimeout.IsNull() {
+		providerData.requestTimeout = time.Duration(config.RequestTimeout.ValueInt64()) * time.Millisecond
+	}
+
+	providerData.responseCache = newResponseCache(
+		config.CacheDir.ValueString(),
+		time.Duration(config.CacheTTL.ValueInt64())*time.Millisecond,
+	)
+
+	if config.Proxy != nil {
+		proxy, err := newProxyConfig(ctx, config.Proxy)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("proxy").AtName("url"),
+				"Invalid proxy configuration",
+				fmt.Sprintf("Invalid proxy configuration: %s", err),
+			)
+			return
+		}
+
+		providerData.proxy = proxy
+	}
+
+	if !config.ProxyPACURL.IsNull() || !config.ProxyPACContent.IsNull() {
+		// The PAC file is downloaded directly, with the provider TLS settings.
+		providerData.proxy = &proxyConfig{}
+		tr, diags := providerData.newTransport(types.StringNull(), types.BoolNull())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		pac, err := newPACResolver(
+			config.ProxyPACURL.ValueString(),
+			config.ProxyPACContent.ValueString(),
+			&http.Client{Transport: tr, Timeout: pacTimeout},
+		)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("proxy_pac_content"),
+				"Invalid PAC file",
+				fmt.Sprintf("Error loading PAC file: %s", err),
+			)
+			return
+		}
+
+		providerData.proxy = &proxyConfig{pac: pac}
+	}
+
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
 }
 
 func (p *httpProvider) Resources(context.Context) []func() resource.Resource {
-	return nil
+	return []func() resource.Resource{
+		NewHttpRequestResource,
+	}
 }
 
 func (p *httpProvider) DataSources(context.Context) []func() datasource.DataSource {
@@ -35,3 +267,105 @@ func (p *httpProvider) DataSources(context.Context) []func() datasource.DataSour
 		NewHttpDataSource,
 	}
 }
+
+// httpProviderData holds the provider level defaults, which are handed to
+// data sources and resources through ConfigureResponse.DataSourceData and
+// ConfigureResponse.ResourceData.
+type httpProviderData struct {
+	baseURL        *url.URL
+	requestHeaders map[string]string
+	caCertificate  types.String
+	insecure       types.Bool
+	requestTimeout time.Duration
+	proxy          *proxyConfig
+	oauth2Tokens   *oauth2TokenCache
+	responseCache  *responseCache
+}
+
+// resolveURL resolves the given URL against the provider base_url, if set.
+func (p *httpProviderData) resolveURL(rawURL string) (string, error) {
+	if p.baseURL == nil {
+		return rawURL, nil
+	}
+
+	ref, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	return p.baseURL.ResolveReference(ref).String(), nil
+}
+
+// newTransport returns a clone of the default transport configured with the
+// provider proxy and TLS settings. The given ca_cert_pem and insecure values
+// take precedence over the provider ones unless they are null. A given
+// ca_cert_pem also takes precedence over the provider insecure, so that it
+// verifies the server certificate.
+func (p *httpProviderData) newTransport(caCertificate types.String, insecure types.Bool) (*http.Transport, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if insecure.IsNull() && caCertificate.IsNull() {
+		insecure = p.insecure
+	}
+
+	if caCertificate.IsNull() {
+		caCertificate = p.caCertificate
+	}
+
+	tr, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		diags.AddError(
+			"Error configuring http transport",
+			"Error http: Can't configure http transport.",
+		)
+		return nil, diags
+	}
+
+	// Prevent issues with multiple configurations modifying the shared transport.
+	clonedTr := tr.Clone()
+
+	if err := configureProxy(clonedTr, p.proxy, &resolvingDialer{dialer: newDialer(defaultConnectTimeout)}); err != nil {
+		diags.AddError(
+			"Error configuring proxy",
+			fmt.Sprintf("Error configuring proxy: %s", err),
+		)
+		return nil, diags
+	}
+
+	if clonedTr.TLSClientConfig == nil {
+		clonedTr.TLSClientConfig = &tls.Config{}
+	}
+
+	if !insecure.IsNull() {
+		clonedTr.TLSClientConfig.InsecureSkipVerify = insecure.ValueBool()
+	}
+
+	// Use `ca_cert_pem` cert pool
+	if !caCertificate.IsNull() {
+		caCertPool := x509.NewCertPool()
+		if ok := caCertPool.AppendCertsFromPEM([]byte(caCertificate.ValueString())); !ok {
+			diags.AddError(
+				"Error configuring TLS client",
+				"Error tls: Can't add the CA certificate to certificate pool. Only PEM encoded certificates are supported.",
+			)
+			return nil, diags
+		}
+
+		clonedTr.TLSClientConfig.RootCAs = caCertPool
+	}
+
+	return clonedTr, diags
+}
+
+type httpProviderModel struct {
+	BaseURL         types.String `tfsdk:"base_url"`
+	RequestHeaders  types.Map    `tfsdk:"request_headers"`
+	CaCertificate   types.String `tfsdk:"ca_cert_pem"`
+	Insecure        types.Bool   `tfsdk:"insecure"`
+	RequestTimeout  types.Int64  `tfsdk:"request_timeout_ms"`
+	CacheDir        types.String `tfsdk:"cache_dir"`
+	CacheTTL        types.Int64  `tfsdk:"cache_ttl_ms"`
+	ProxyPACURL     types.String `tfsdk:"proxy_pac_url"`
+	ProxyPACContent types.String `tfsdk:"proxy_pac_content"`
+	Proxy           *proxyModel  `tfsdk:"proxy"`
+}
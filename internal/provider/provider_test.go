@@ -1,8 +1,18 @@
 package provider
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
 //nolint:unparam
@@ -11,3 +21,359 @@ func protoV5ProviderFactories() map[string]func() (tfprotov5.ProviderServer, err
 		"http": providerserver.NewProtocol5WithError(New()),
 	}
 }
+
+func TestProvider_BaseURL(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(false)
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							provider "http" {
+								base_url = "%s"
+							}
+
+							data "http" "http_test" {
+								url = "/200"
+							}`, testHttpMock.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "id", fmt.Sprintf("%s/200", testHttpMock.server.URL)),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "1.0.0"),
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				),
+			},
+		},
+	})
+}
+
+func TestProvider_BaseURLInvalid(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+							provider "http" {
+								base_url = "/relative"
+							}
+
+							data "http" "http_test" {
+								url = "/200"
+							}`,
+				ExpectError: regexp.MustCompile(`expected an absolute http or https URL`),
+			},
+		},
+	})
+}
+
+func TestProvider_RequestHeaders(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(false)
+	defer testHttpMock.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							provider "http" {
+								request_headers = {
+									"Authorization" = "Zm9vOmJhcg=="
+								}
+							}
+
+							data "http" "http_test" {
+								url = "%s/restricted"
+							}`, testHttpMock.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "1.0.0"),
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							provider "http" {
+								request_headers = {
+									"Authorization" = "Zm9vOmJhcg=="
+								}
+							}
+
+							data "http" "http_test" {
+								url = "%s/restricted"
+
+								request_headers = {
+									"authorization" = "unauthorized"
+								}
+							}`, testHttpMock.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "403"),
+				),
+			},
+		},
+	})
+}
+
+func TestProvider_CACertificate(t *testing.T) {
+	testHttpMock := setUpMockHttpServer(true)
+	defer testHttpMock.server.Close()
+
+	otherCA, _ := testClientCertificate(t, "other")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							provider "http" {
+								ca_cert_pem = <<EOF
+%s
+EOF
+							}
+
+							data "http" "http_test" {
+								url = "%s/200"
+							}`, CertToPEM(testHttpMock.server.Certificate()), testHttpMock.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							provider "http" {
+								insecure = true
+							}
+
+							data "http" "http_test" {
+								url = "%s/200"
+
+								insecure = false
+							}`, testHttpMock.server.URL),
+				ExpectError: regexp.MustCompile(fmt.Sprintf(`Error making request: Get "%s/200": x509: `, testHttpMock.server.URL)),
+			},
+			{
+				// The data source ca_cert_pem takes precedence over the provider
+				// insecure, so the server certificate is verified against it.
+				Config: fmt.Sprintf(`
+							provider "http" {
+								insecure = true
+							}
+
+							data "http" "http_test" {
+								url = "%s/200"
+
+								ca_cert_pem = <<EOF
+%s
+EOF
+							}`, testHttpMock.server.URL, otherCA),
+				ExpectError: regexp.MustCompile(fmt.Sprintf(`Error making request: Get "%s/200": x509: `, testHttpMock.server.URL)),
+			},
+		},
+	})
+}
+
+func TestProvider_RequestTimeout(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer svr.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							provider "http" {
+								request_timeout_ms = 10
+							}
+
+							data "http" "http_test" {
+								url = "%s"
+							}`, svr.URL),
+				ExpectError: regexp.MustCompile(`The request timed out while waiting for the response headers`),
+			},
+		},
+	})
+}
+
+func TestProvider_Proxy(t *testing.T) {
+	var proxyRequests, serverRequests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverRequests++
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("error parsing server URL: %s", err)
+	}
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxyRequests++
+		httputil.NewSingleHostReverseProxy(serverURL).ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							provider "http" {
+								proxy {
+									url = "%s"
+								}
+							}
+
+							data "http" "http_test" {
+								url = "%s"
+							}`, proxy.URL, testProxiedURL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					CheckServerAndProxyRequestCount(&proxyRequests, &serverRequests),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							provider "http" {
+								proxy {
+									no_proxy = ["example.com"]
+								}
+							}
+
+							data "http" "http_test" {
+								url = "%s"
+							}`, server.URL),
+				ExpectError: regexp.MustCompile(`Attribute "proxy.url" must be specified when "proxy" is specified`),
+			},
+		},
+	})
+}
+
+func TestProvider_ProxySOCKS5(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(r.Host))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("error parsing server URL: %s", err)
+	}
+
+	proxyAddr, _ := startSOCKS5Proxy(t, "user", "secret", serverURL.Host)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							provider "http" {
+								proxy {
+									url      = "socks5h://%s"
+									username = "user"
+									password = "secret"
+								}
+							}
+
+							data "http" "http_test" {
+								url = "%s"
+							}`, proxyAddr, testProxiedURL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.http.http_test", "response_body", "terraform-provider-http-test-proxy"),
+				),
+			},
+		},
+	})
+}
+
+func TestProvider_ProxyPAC(t *testing.T) {
+	var proxyRequests, serverRequests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverRequests++
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("error parsing server URL: %s", err)
+	}
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxyRequests++
+		httputil.NewSingleHostReverseProxy(serverURL).ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatalf("error parsing proxy URL: %s", err)
+	}
+
+	pac := fmt.Sprintf(`function FindProxyForURL(url, host) {
+		if (dnsDomainIs(host, "terraform-provider-http-test-proxy")) {
+			return "PROXY %s";
+		}
+		return "DIRECT";
+	}`, proxyURL.Host)
+
+	pacServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ns-proxy-autoconfig")
+		_, _ = w.Write([]byte(pac))
+	}))
+	defer pacServer.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							provider "http" {
+								proxy_pac_url = "%s"
+							}
+
+							data "http" "http_test" {
+								url = "%s"
+							}`, pacServer.URL, testProxiedURL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					CheckServerAndProxyRequestCount(&proxyRequests, &serverRequests),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							provider "http" {
+								proxy_pac_content = %q
+							}
+
+							data "http" "http_test" {
+								url = "%s"
+							}`, pac, testProxiedURL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.http.http_test", "status_code", "200"),
+					CheckServerAndProxyRequestCount(&proxyRequests, &serverRequests),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							provider "http" {
+								proxy_pac_content = "function FindProxyForURL(url, host) {"
+							}
+
+							data "http" "http_test" {
+								url = "%s"
+							}`, server.URL),
+				ExpectError: regexp.MustCompile("Invalid PAC file"),
+			},
+		},
+	})
+}
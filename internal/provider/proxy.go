@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+type proxyModel struct {
+	URL      types.String `tfsdk:"url"`
+	Username types.String `tfsdk:"username"`
+	Password types.String `tfsdk:"password"`
+	NoProxy  types.List   `tfsdk:"no_proxy"`
+}
+
+// proxyConfig is the configuration of a proxy block or PAC file. A nil
+// *proxyConfig stands for the proxy given by the environment variables.
+type proxyConfig struct {
+	// url is nil when requests are sent directly.
+	url     *url.URL
+	noProxy string
+
+	// pac picks the proxy of each request instead of url when set.
+	pac *pacResolver
+}
+
+// newProxyConfig builds the proxy configuration from a proxy block. The
+// username and password take precedence over any in the URL.
+func newProxyConfig(ctx context.Context, model *proxyModel) (*proxyConfig, error) {
+	config := &proxyConfig{}
+
+	if !model.NoProxy.IsNull() {
+		var noProxy []string
+		if diags := model.NoProxy.ElementsAs(ctx, &noProxy, false); diags.HasError() {
+			return nil, errors.New("reading no_proxy")
+		}
+
+		config.noProxy = strings.Join(noProxy, ",")
+	}
+
+	if model.URL.IsNull() {
+		return config, nil
+	}
+
+	u, err := url.Parse(model.URL.ValueString())
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q, expected http, https, socks5 or socks5h", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", model.URL.ValueString())
+	}
+
+	if !model.Username.IsNull() {
+		if model.Password.IsNull() {
+			u.User = url.User(model.Username.ValueString())
+		} else {
+			u.User = url.UserPassword(model.Username.ValueString(), model.Password.ValueString())
+		}
+	}
+
+	config.url = u
+
+	return config, nil
+}
+
+// configureProxy sets the proxy of the transport, which dials connections
+// with dialer. HTTP and HTTPS proxies are used through http.Transport.Proxy,
+// which sends the credentials in the Proxy-Authorization header, while SOCKS5
+// proxies are dialed through. A socks5 proxy is sent the address resolved
+// locally, and a socks5h proxy the host name, which it resolves.
+func configureProxy(tr *http.Transport, config *proxyConfig, dialer *resolvingDialer) error {
+	tr.DialContext = dialer.DialContext
+
+	if config == nil {
+		// The environment is read for each request to prevent issues with
+		// tests caching the proxy configuration.
+		tr.Proxy = func(req *http.Request) (*url.URL, error) {
+			return httpproxy.FromEnvironment().ProxyFunc()(req.URL)
+		}
+		return nil
+	}
+
+	if config.pac != nil {
+		tr.Proxy = config.pac.proxy
+		return nil
+	}
+
+	tr.Proxy = nil
+	if config.url == nil {
+		return nil
+	}
+
+	if config.url.Scheme == "http" || config.url.Scheme == "https" {
+		proxyFunc := (&httpproxy.Config{
+			HTTPProxy:  config.url.String(),
+			HTTPSProxy: config.url.String(),
+			NoProxy:    config.noProxy,
+		}).ProxyFunc()
+
+		tr.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+		return nil
+	}
+
+	// The proxy URL is only used to decide whether an address is requested
+	// through the proxy, and httpproxy does not accept the socks5h scheme.
+	useProxyFunc := (&httpproxy.Config{
+		HTTPSProxy: "socks5://" + config.url.Host,
+		NoProxy:    config.noProxy,
+	}).ProxyFunc()
+
+	var auth *proxy.Auth
+	if config.url.User != nil {
+		password, _ := config.url.User.Password()
+		auth = &proxy.Auth{
+			User:     config.url.User.Username(),
+			Password: password,
+		}
+	}
+
+	socksDialer, err := proxy.SOCKS5("tcp", config.url.Host, auth, dialer)
+	if err != nil {
+		return err
+	}
+
+	contextDialer, ok := socksDialer.(proxy.ContextDialer)
+	if !ok {
+		return errors.New("SOCKS5 dialer does not support contexts")
+	}
+
+	remoteResolve := config.url.Scheme == "socks5h"
+
+	tr.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		// The no_proxy list matches host names and addresses, regardless of
+		// the scheme.
+		proxyURL, err := useProxyFunc(&url.URL{Scheme: "https", Host: addr})
+		if err != nil {
+			return nil, err
+		}
+		if proxyURL == nil {
+			return dialer.DialContext(ctx, network, addr)
+		}
+
+		if !remoteResolve {
+			addr, err = dialer.resolve(ctx, addr)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return contextDialer.DialContext(ctx, network, addr)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// appendQueryParams encodes the query_params into the query string of the
+// URL. The parameters follow any query already in the URL, which is kept as
+// is, and are sorted by name with repeated values in order.
+func appendQueryParams(ctx context.Context, rawURL string, queryParams types.Map) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if queryParams.IsNull() {
+		return rawURL, diags
+	}
+
+	var params map[string][]string
+	diags.Append(queryParams.ElementsAs(ctx, &params, false)...)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("url"),
+			"Error parsing URL",
+			fmt.Sprintf("Error parsing URL to add query_params: %s", err),
+		)
+		return "", diags
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	query := []string{}
+	if u.RawQuery != "" {
+		query = append(query, u.RawQuery)
+	}
+
+	for _, name := range names {
+		for _, value := range params[name] {
+			query = append(query, url.QueryEscape(name)+"="+url.QueryEscape(value))
+		}
+	}
+
+	u.RawQuery = strings.Join(query, "&")
+	u.ForceQuery = false
+
+	return u.String(), diags
+}
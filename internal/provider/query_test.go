@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestAppendQueryParams(t *testing.T) {
+	listType := types.ListType{ElemType: types.StringType}
+
+	testCases := map[string]struct {
+		url         string
+		queryParams types.Map
+		expected    string
+	}{
+		"null": {
+			url:         "https://example.com/path?a=1",
+			queryParams: types.MapNull(listType),
+			expected:    "https://example.com/path?a=1",
+		},
+		"encoded": {
+			url: "https://example.com/path",
+			queryParams: types.MapValueMust(listType, map[string]attr.Value{
+				"q":     types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a b&c=d")}),
+				"a key": types.ListValueMust(types.StringType, []attr.Value{types.StringValue("ü")}),
+			}),
+			expected: "https://example.com/path?a+key=%C3%BC&q=a+b%26c%3Dd",
+		},
+		"repeated": {
+			url: "https://example.com/path",
+			queryParams: types.MapValueMust(listType, map[string]attr.Value{
+				"tag": types.ListValueMust(types.StringType, []attr.Value{
+					types.StringValue("b"),
+					types.StringValue("a"),
+				}),
+				"empty": types.ListValueMust(types.StringType, []attr.Value{}),
+			}),
+			expected: "https://example.com/path?tag=b&tag=a",
+		},
+		"merged": {
+			url: "https://example.com/path?z=1&tag=x#fragment",
+			queryParams: types.MapValueMust(listType, map[string]attr.Value{
+				"tag": types.ListValueMust(types.StringType, []attr.Value{types.StringValue("y")}),
+			}),
+			expected: "https://example.com/path?z=1&tag=x&tag=y#fragment",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, diags := appendQueryParams(context.Background(), testCase.url, testCase.queryParams)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if actual != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, actual)
+			}
+		})
+	}
+}
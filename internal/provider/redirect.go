@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// defaultMaxRedirects matches the number of redirects followed by the default
+// http.Client policy.
+const defaultMaxRedirects = 10
+
+// redirectPolicy decides which redirects are followed by the client.
+type redirectPolicy struct {
+	follow         bool
+	maxRedirects   int
+	allowCrossHost bool
+	allowInsecure  bool
+}
+
+// checkRedirect implements http.Client.CheckRedirect. The req is the upcoming
+// request and via holds the requests made so far, oldest first.
+func (p *redirectPolicy) checkRedirect(req *http.Request, via []*http.Request) error {
+	if !p.follow {
+		return http.ErrUseLastResponse
+	}
+
+	if len(via) > p.maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", p.maxRedirects)
+	}
+
+	previous := via[len(via)-1]
+
+	if !p.allowCrossHost && req.URL.Host != previous.URL.Host {
+		return fmt.Errorf("refusing redirect from host %q to host %q", previous.URL.Host, req.URL.Host)
+	}
+
+	if !p.allowInsecure && previous.URL.Scheme == "https" && req.URL.Scheme == "http" {
+		return fmt.Errorf("refusing redirect from https to http URL %q", req.URL)
+	}
+
+	return nil
+}
+
+// redirectChain returns the URLs requested to obtain the response, in order,
+// starting with the original request URL and ending with the final one.
+func redirectChain(response *http.Response) []string {
+	var chain []string
+
+	for req := response.Request; req != nil; {
+		chain = append([]string{req.URL.String()}, chain...)
+
+		if req.Response == nil {
+			break
+		}
+		req = req.Response.Request
+	}
+
+	return chain
+}
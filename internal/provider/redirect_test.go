@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestRedirectPolicy_checkRedirect(t *testing.T) {
+	testCases := map[string]struct {
+		policy      redirectPolicy
+		from        string
+		to          string
+		via         int
+		expectError bool
+	}{
+		"follow": {
+			policy: redirectPolicy{follow: true, maxRedirects: 10, allowCrossHost: true, allowInsecure: true},
+			from:   "https://example.com/a",
+			to:     "http://example.org/b",
+			via:    1,
+		},
+		"max-redirects": {
+			policy:      redirectPolicy{follow: true, maxRedirects: 2, allowCrossHost: true, allowInsecure: true},
+			from:        "https://example.com/a",
+			to:          "https://example.com/b",
+			via:         3,
+			expectError: true,
+		},
+		"cross-host": {
+			policy:      redirectPolicy{follow: true, maxRedirects: 10, allowInsecure: true},
+			from:        "https://example.com/a",
+			to:          "https://example.org/b",
+			via:         1,
+			expectError: true,
+		},
+		"same-host": {
+			policy: redirectPolicy{follow: true, maxRedirects: 10},
+			from:   "https://example.com/a",
+			to:     "https://example.com/b",
+			via:    1,
+		},
+		"insecure": {
+			policy:      redirectPolicy{follow: true, maxRedirects: 10, allowCrossHost: true},
+			from:        "https://example.com/a",
+			to:          "http://example.com/b",
+			via:         1,
+			expectError: true,
+		},
+		"upgrade": {
+			policy: redirectPolicy{follow: true, maxRedirects: 10, allowCrossHost: true},
+			from:   "http://example.com/a",
+			to:     "https://example.com/b",
+			via:    1,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			from, _ := url.Parse(testCase.from)
+			to, _ := url.Parse(testCase.to)
+
+			via := make([]*http.Request, testCase.via)
+			for i := range via {
+				via[i] = &http.Request{URL: from}
+			}
+
+			err := testCase.policy.checkRedirect(&http.Request{URL: to}, via)
+			if testCase.expectError != (err != nil) {
+				t.Errorf("expected error %t, got %v", testCase.expectError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	contentTypeForm        = "application/x-www-form-urlencoded"
+	defaultFileContentType = "application/octet-stream"
+)
+
+type multipartModel struct {
+	Fields types.Map            `tfsdk:"fields"`
+	Files  []multipartFileModel `tfsdk:"file"`
+}
+
+type multipartFileModel struct {
+	Name          types.String `tfsdk:"name"`
+	Filename      types.String `tfsdk:"filename"`
+	ContentType   types.String `tfsdk:"content_type"`
+	Content       types.String `tfsdk:"content"`
+	ContentBase64 types.String `tfsdk:"content_base64"`
+	Source        types.String `tfsdk:"source"`
+}
+
+// newRequestBody encodes the request body configured by one of request_body,
+// sensitive_request_body, request_form or request_multipart. The returned
+// content type is empty for a raw request body.
+func newRequestBody(ctx context.Context, model modelV0) ([]byte, string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	switch {
+	case !model.SensitiveRequestBody.IsNull():
+		return []byte(model.SensitiveRequestBody.ValueString()), "", diags
+	case !model.RequestForm.IsNull():
+		var fields map[string]string
+		diags.Append(model.RequestForm.ElementsAs(ctx, &fields, false)...)
+		if diags.HasError() {
+			return nil, "", diags
+		}
+
+		values := url.Values{}
+		for name, value := range fields {
+			values.Set(name, value)
+		}
+
+		return []byte(values.Encode()), contentTypeForm, diags
+	case model.RequestMultipart != nil:
+		return newMultipartBody(ctx, model.RequestMultipart)
+	}
+
+	return []byte(model.RequestBody.ValueString()), "", diags
+}
+
+// newMultipartBody encodes a multipart/form-data body, with the fields in
+// order of name followed by the files in order of configuration.
+func newMultipartBody(ctx context.Context, model *multipartModel) ([]byte, string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	var fields map[string]string
+	if !model.Fields.IsNull() {
+		diags.Append(model.Fields.ElementsAs(ctx, &fields, false)...)
+		if diags.HasError() {
+			return nil, "", diags
+		}
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := writer.WriteField(name, fields[name]); err != nil {
+			diags.AddError("Error encoding multipart request body", err.Error())
+			return nil, "", diags
+		}
+	}
+
+	for i, file := range model.Files {
+		filePath := path.Root("request_multipart").AtName("file").AtListIndex(i)
+
+		// Servers tell files from fields by their file name, which must not be
+		// empty, so it defaults to the name of the part.
+		var content []byte
+		filename := file.Name.ValueString()
+		if !file.Filename.IsNull() {
+			filename = file.Filename.ValueString()
+		}
+
+		switch {
+		case !file.ContentBase64.IsNull():
+			var err error
+			content, err = base64.StdEncoding.DecodeString(file.ContentBase64.ValueString())
+			if err != nil {
+				diags.AddAttributeError(
+					filePath.AtName("content_base64"),
+					"Invalid file content",
+					fmt.Sprintf("Error decoding file content, which must be base64 encoded: %s", err),
+				)
+				return nil, "", diags
+			}
+		case !file.Source.IsNull():
+			var err error
+			content, err = os.ReadFile(file.Source.ValueString())
+			if err != nil {
+				diags.AddAttributeError(
+					filePath.AtName("source"),
+					"Error reading file",
+					fmt.Sprintf("Error reading file: %s", err),
+				)
+				return nil, "", diags
+			}
+			if file.Filename.IsNull() {
+				filename = filepath.Base(file.Source.ValueString())
+			}
+		default:
+			content = []byte(file.Content.ValueString())
+		}
+
+		contentType := defaultFileContentType
+		if !file.ContentType.IsNull() {
+			contentType = file.ContentType.ValueString()
+		}
+
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			escapeQuotes(file.Name.ValueString()), escapeQuotes(filename)))
+		header.Set("Content-Type", contentType)
+
+		part, err := writer.CreatePart(header)
+		if err == nil {
+			_, err = part.Write(content)
+		}
+		if err != nil {
+			diags.AddError("Error encoding multipart request body", err.Error())
+			return nil, "", diags
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		diags.AddError("Error encoding multipart request body", err.Error())
+		return nil, "", diags
+	}
+
+	return body.Bytes(), writer.FormDataContentType(), diags
+}
+
+// escapeQuotes escapes a Content-Disposition parameter value in the same way
+// as mime/multipart.
+func escapeQuotes(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
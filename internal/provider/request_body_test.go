@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNewRequestBody(t *testing.T) {
+	testCases := map[string]struct {
+		model               modelV0
+		expectedBody        string
+		expectedContentType string
+	}{
+		"none": {
+			model: modelV0{
+				RequestForm: types.MapNull(types.StringType),
+			},
+		},
+		"body": {
+			model: modelV0{
+				RequestBody:          types.StringValue("plain"),
+				SensitiveRequestBody: types.StringNull(),
+				RequestForm:          types.MapNull(types.StringType),
+			},
+			expectedBody: "plain",
+		},
+		"sensitive-body": {
+			model: modelV0{
+				RequestBody:          types.StringNull(),
+				SensitiveRequestBody: types.StringValue("secret"),
+				RequestForm:          types.MapNull(types.StringType),
+			},
+			expectedBody: "secret",
+		},
+		"form": {
+			model: modelV0{
+				RequestBody:          types.StringNull(),
+				SensitiveRequestBody: types.StringNull(),
+				RequestForm: types.MapValueMust(types.StringType, map[string]attr.Value{
+					"b": types.StringValue("a&b=c"),
+					"a": types.StringValue("1 2"),
+				}),
+			},
+			expectedBody:        "a=1+2&b=a%26b%3Dc",
+			expectedContentType: "application/x-www-form-urlencoded",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			body, contentType, diags := newRequestBody(context.Background(), testCase.model)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if string(body) != testCase.expectedBody {
+				t.Errorf("expected body %q, got %q", testCase.expectedBody, body)
+			}
+
+			if contentType != testCase.expectedContentType {
+				t.Errorf("expected content type %q, got %q", testCase.expectedContentType, contentType)
+			}
+		})
+	}
+}
+
+func TestNewMultipartBody(t *testing.T) {
+	model := &multipartModel{
+		Fields: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"b": types.StringValue("2"),
+			"a": types.StringValue("1"),
+		}),
+		Files: []multipartFileModel{
+			{
+				Name:          types.StringValue("text"),
+				Filename:      types.StringValue(`say "hi".txt`),
+				ContentType:   types.StringValue("text/plain"),
+				Content:       types.StringValue("hi"),
+				ContentBase64: types.StringNull(),
+				Source:        types.StringNull(),
+			},
+			{
+				Name:          types.StringValue("binary"),
+				Filename:      types.StringNull(),
+				ContentType:   types.StringNull(),
+				Content:       types.StringNull(),
+				ContentBase64: types.StringValue("AAEC"),
+				Source:        types.StringNull(),
+			},
+		},
+	}
+
+	body, contentType, diags := newMultipartBody(context.Background(), model)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if mediaType != "multipart/form-data" {
+		t.Fatalf("expected multipart/form-data, got %q", mediaType)
+	}
+
+	expected := []struct {
+		name        string
+		filename    string
+		contentType string
+		content     string
+	}{
+		{name: "a", content: "1"},
+		{name: "b", content: "2"},
+		{name: "text", filename: `say "hi".txt`, contentType: "text/plain", content: "hi"},
+		{name: "binary", filename: "binary", contentType: "application/octet-stream", content: "\x00\x01\x02"},
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for _, e := range expected {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("unexpected error reading part %q: %s", e.name, err)
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("unexpected error reading part %q: %s", e.name, err)
+		}
+
+		if part.FormName() != e.name || part.FileName() != e.filename ||
+			part.Header.Get("Content-Type") != e.contentType || string(content) != e.content {
+			t.Errorf("expected part %q %q %q %q, got %q %q %q %q",
+				e.name, e.filename, e.contentType, e.content,
+				part.FormName(), part.FileName(), part.Header.Get("Content-Type"), content)
+		}
+	}
+
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("expected end of body, got %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultDNSPort is the port of DNS servers given without one.
+const defaultDNSPort = "53"
+
+// resolvingDialer dials connections like net.Dialer, with host name
+// overrides and custom DNS servers, in the same way as the curl --resolve
+// and --dns-servers options.
+type resolvingDialer struct {
+	dialer *net.Dialer
+
+	// overrides maps host:port addresses to the IP address dialed instead of
+	// resolving the host name.
+	overrides map[string]string
+
+	// resolver queries the custom DNS servers, or is nil to use the system
+	// resolver.
+	resolver *net.Resolver
+}
+
+// parseResolveOverrides validates the resolve overrides, whose keys must be
+// host:port addresses and values IP addresses, and lowercases their host
+// names.
+func parseResolveOverrides(overrides map[string]string) (map[string]string, error) {
+	parsed := make(map[string]string, len(overrides))
+
+	for addr, ip := range overrides {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("expected a host:port address, got %q", addr)
+		}
+
+		if net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("expected an IP address for %q, got %q", addr, ip)
+		}
+
+		parsed[net.JoinHostPort(strings.ToLower(host), port)] = ip
+	}
+
+	return parsed, nil
+}
+
+// parseDNSServers validates the DNS servers, which are IP addresses with an
+// optional port, and adds the default port to those without one.
+func parseDNSServers(dnsServers []string) ([]string, error) {
+	servers := make([]string, len(dnsServers))
+
+	for i, server := range dnsServers {
+		if net.ParseIP(server) != nil {
+			servers[i] = net.JoinHostPort(server, defaultDNSPort)
+			continue
+		}
+
+		host, _, err := net.SplitHostPort(server)
+		if err != nil || net.ParseIP(host) == nil {
+			return nil, fmt.Errorf("expected an IP address with an optional port, got %q", server)
+		}
+
+		servers[i] = server
+	}
+
+	return servers, nil
+}
+
+// newResolvingDialer returns a dialer with the overrides and DNS servers
+// returned by parseResolveOverrides and parseDNSServers.
+func newResolvingDialer(dialer *net.Dialer, overrides map[string]string, dnsServers []string) *resolvingDialer {
+	d := &resolvingDialer{
+		dialer:    dialer,
+		overrides: overrides,
+	}
+
+	if len(dnsServers) > 0 {
+		// The resolver dials a server for each query and retries a failed
+		// query, so the servers are queried in turn.
+		var next uint32
+		d.resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				server := dnsServers[int(atomic.AddUint32(&next, 1)-1)%len(dnsServers)]
+				return dialer.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	return d
+}
+
+// lookup returns the IP addresses to dial for the address, or nil if the
+// address is dialed as is.
+func (d *resolvingDialer) lookup(ctx context.Context, addr string) ([]string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if ip, ok := d.overrides[net.JoinHostPort(strings.ToLower(host), port)]; ok {
+		return []string{net.JoinHostPort(ip, port)}, nil
+	}
+
+	if d.resolver == nil || net.ParseIP(host) != nil {
+		return nil, nil
+	}
+
+	ips, err := d.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		// The error names the system DNS server the resolver would have
+		// queried, rather than that of dns_servers.
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) {
+			dnsErrCopy := *dnsErr
+			dnsErrCopy.Server = ""
+			err = &dnsErrCopy
+		}
+
+		return nil, err
+	}
+
+	addrs := make([]string, len(ips))
+	for i, ip := range ips {
+		addrs[i] = net.JoinHostPort(ip.IP.String(), port)
+	}
+
+	return addrs, nil
+}
+
+// resolve returns the address with the host name resolved to its first IP
+// address, as sent to a SOCKS5 proxy which does not resolve host names.
+func (d *resolvingDialer) resolve(ctx context.Context, addr string) (string, error) {
+	addrs, err := d.lookup(ctx, addr)
+	if err != nil {
+		return "", err
+	}
+
+	if len(addrs) > 0 {
+		return addrs[0], nil
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(ips[0].IP.String(), port), nil
+}
+
+// Dial implements proxy.Dialer, which is required along with
+// proxy.ContextDialer for the forward dialer of a SOCKS5 proxy.
+func (d *resolvingDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, addr)
+}
+
+// DialContext implements http.Transport.DialContext, dialing each IP address
+// of the host in turn until a connection is made.
+func (d *resolvingDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	addrs, err := d.lookup(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(addrs) == 0 {
+		return d.dialer.DialContext(ctx, network, addr)
+	}
+
+	var conn net.Conn
+	for _, a := range addrs {
+		conn, err = d.dialer.DialContext(ctx, network, a)
+		if err == nil {
+			return conn, nil
+		}
+	}
+
+	return nil, err
+}
+
+// remoteIP records the IP address of the connection each request is sent on,
+// so that once the request completes it holds that of the final response.
+type remoteIP struct {
+	mu sync.Mutex
+	ip string
+}
+
+// clientTrace returns the hook which records the address of the connection.
+func (r *remoteIP) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+
+			r.ip = ""
+			if addr, ok := info.Conn.RemoteAddr().(*net.TCPAddr); ok {
+				r.ip = addr.IP.String()
+			}
+		},
+	}
+}
+
+// String returns the recorded IP address, or an empty string if no
+// connection was made.
+func (r *remoteIP) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.ip
+}
@@ -0,0 +1,191 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParseResolveOverrides(t *testing.T) {
+	testCases := map[string]struct {
+		overrides   map[string]string
+		expected    map[string]string
+		expectedErr bool
+	}{
+		"none": {
+			expected: map[string]string{},
+		},
+		"valid": {
+			overrides: map[string]string{
+				"WWW.Example.com:443": "192.0.2.10",
+				"[2001:db8::1]:80":    "2001:db8::2",
+			},
+			expected: map[string]string{
+				"www.example.com:443": "192.0.2.10",
+				"[2001:db8::1]:80":    "2001:db8::2",
+			},
+		},
+		"no-port": {
+			overrides:   map[string]string{"www.example.com": "192.0.2.10"},
+			expectedErr: true,
+		},
+		"not-ip": {
+			overrides:   map[string]string{"www.example.com:443": "backend.example.com"},
+			expectedErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parseResolveOverrides(testCase.overrides)
+			if testCase.expectedErr {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(actual) != len(testCase.expected) {
+				t.Fatalf("expected %v, got %v", testCase.expected, actual)
+			}
+
+			for addr, ip := range testCase.expected {
+				if actual[addr] != ip {
+					t.Errorf("expected %q for %q, got %q", ip, addr, actual[addr])
+				}
+			}
+		})
+	}
+}
+
+func TestParseDNSServers(t *testing.T) {
+	testCases := map[string]struct {
+		servers     []string
+		expected    []string
+		expectedErr bool
+	}{
+		"ipv4": {
+			servers:  []string{"192.0.2.53"},
+			expected: []string{"192.0.2.53:53"},
+		},
+		"ipv6": {
+			servers:  []string{"2001:db8::53"},
+			expected: []string{"[2001:db8::53]:53"},
+		},
+		"port": {
+			servers:  []string{"192.0.2.53:5353", "[2001:db8::53]:5353"},
+			expected: []string{"192.0.2.53:5353", "[2001:db8::53]:5353"},
+		},
+		"host-name": {
+			servers:     []string{"dns.example.com"},
+			expectedErr: true,
+		},
+		"host-name-port": {
+			servers:     []string{"dns.example.com:53"},
+			expectedErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parseDNSServers(testCase.servers)
+			if testCase.expectedErr {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(actual) != len(testCase.expected) {
+				t.Fatalf("expected %v, got %v", testCase.expected, actual)
+			}
+
+			for i := range actual {
+				if actual[i] != testCase.expected[i] {
+					t.Errorf("expected %q, got %q", testCase.expected[i], actual[i])
+				}
+			}
+		})
+	}
+}
+
+func TestResolvingDialer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("error parsing server URL: %s", err)
+	}
+
+	_, port, err := net.SplitHostPort(serverURL.Host)
+	if err != nil {
+		t.Fatalf("error parsing server address: %s", err)
+	}
+
+	dnsServer := startDNSServer(t, map[string]string{"backend.example.test": "127.0.0.1"})
+
+	dialer := newResolvingDialer(
+		newDialer(defaultConnectTimeout),
+		map[string]string{net.JoinHostPort("www.example.com", port): "127.0.0.1"},
+		[]string{dnsServer},
+	)
+
+	testCases := map[string]struct {
+		addr        string
+		expectedErr bool
+	}{
+		"override": {
+			addr: net.JoinHostPort("WWW.example.com", port),
+		},
+		"dns-server": {
+			addr: net.JoinHostPort("backend.example.test", port),
+		},
+		"ip": {
+			addr: serverURL.Host,
+		},
+		"not-found": {
+			addr:        net.JoinHostPort("missing.example.test", port),
+			expectedErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conn, err := dialer.DialContext(context.Background(), "tcp", testCase.addr)
+			if testCase.expectedErr {
+				if err == nil {
+					conn.Close()
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			defer conn.Close()
+
+			if conn.RemoteAddr().String() != serverURL.Host {
+				t.Errorf("expected connection to %s, got %s", serverURL.Host, conn.RemoteAddr())
+			}
+		})
+	}
+
+	addr, err := dialer.resolve(context.Background(), net.JoinHostPort("backend.example.test", "80"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if addr != "127.0.0.1:80" {
+		t.Errorf("expected 127.0.0.1:80, got %s", addr)
+	}
+}
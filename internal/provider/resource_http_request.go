@@ -0,0 +1,508 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// idPlaceholder is replaced by the resource id in the URL and body of the
+// read, update and delete requests.
+const idPlaceholder = "{id}"
+
+var (
+	_ resource.Resource              = (*httpRequestResource)(nil)
+	_ resource.ResourceWithConfigure = (*httpRequestResource)(nil)
+)
+
+func NewHttpRequestResource() resource.Resource {
+	return &httpRequestResource{
+		providerData: &httpProviderData{},
+	}
+}
+
+type httpRequestResource struct {
+	providerData *httpProviderData
+}
+
+func (r *httpRequestResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_request"
+}
+
+func (r *httpRequestResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*httpProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *httpProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *httpRequestResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `
+The ` + "`http_request`" + ` resource manages a remote object through a generic HTTP API,
+by sending a request when it is created, updated and destroyed.
+
+The response to the most recent request is stored in the resource state. When a
+` + "`read`" + ` request is configured, it is sent whenever the resource is refreshed: a
+` + "`404 Not Found`" + ` or ` + "`410 Gone`" + ` response removes the resource from the state, so
+that it is created again, and any other change to the response is reported as
+a change made outside of Terraform.
+
+Requests which do not result in a ` + "`2xx`" + ` status code fail. The URL and body of the
+` + "`read`" + `, ` + "`update`" + ` and ` + "`delete`" + ` requests may contain the ` + "`{id}`" + ` placeholder,
+which is replaced by the resource ` + "`id`" + `.`,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The identifier of the remote object. This is the value of the `id_attribute` field " +
+					"of the create response when set, otherwise the URL of the create request.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"id_attribute": schema.StringAttribute{
+				Description: "The path of the field holding the identifier of the remote object in the JSON " +
+					"body of the create response, with the names of nested fields separated by dots, " +
+					"such as `data.id`.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"status_code": schema.Int64Attribute{
+				Description: "The HTTP status code of the most recent response.",
+				Computed:    true,
+			},
+
+			"response_body": schema.StringAttribute{
+				Description: "The body of the most recent response returned as a string.",
+				Computed:    true,
+			},
+
+			"response_headers": schema.MapAttribute{
+				Description: `A map of the header field names and values of the most recent response.` +
+					` Duplicate headers are concatenated according to [RFC2616](https://www.w3.org/Protocols/rfc2616/rfc2616-sec4.html#sec4.2).`,
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"create": schema.SingleNestedBlock{
+				Description: "The request sent when the resource is created. Changes to this request replace " +
+					"the resource, unless an `update` request is configured. The method defaults to `POST`.",
+				Attributes: httpRequestBlockAttributes(),
+				Validators: []validator.Object{
+					objectvalidator.IsRequired(),
+					objectvalidator.AlsoRequires(path.MatchRelative().AtName("url")),
+				},
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplaceIf(
+						requiresReplaceWithoutUpdate,
+						"Changes require replacement when no update request is configured.",
+						"Changes require replacement when no `update` request is configured.",
+					),
+				},
+			},
+
+			"read": schema.SingleNestedBlock{
+				Description: "The request sent when the resource is refreshed. The method defaults to `GET`.",
+				Attributes:  httpRequestBlockAttributes(),
+				Validators: []validator.Object{
+					objectvalidator.AlsoRequires(path.MatchRelative().AtName("url")),
+				},
+			},
+
+			"update": schema.SingleNestedBlock{
+				Description: "The request sent when the `create` or `update` request changes. " +
+					"The method defaults to `PUT`.",
+				Attributes: httpRequestBlockAttributes(),
+				Validators: []validator.Object{
+					objectvalidator.AlsoRequires(path.MatchRelative().AtName("url")),
+				},
+			},
+
+			"delete": schema.SingleNestedBlock{
+				Description: "The request sent when the resource is destroyed. A `404 Not Found` or " +
+					"`410 Gone` response is considered successful. The method defaults to `DELETE`. " +
+					"When not set, the resource is only removed from the state.",
+				Attributes: httpRequestBlockAttributes(),
+				Validators: []validator.Object{
+					objectvalidator.AlsoRequires(path.MatchRelative().AtName("url")),
+				},
+			},
+		},
+	}
+}
+
+// httpRequestBlockAttributes returns the attributes shared by the blocks
+// describing the lifecycle requests.
+func httpRequestBlockAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"url": schema.StringAttribute{
+			Description: "The URL for the request, which must be set. Supported schemes are `http` and `https`. " +
+				"A relative URL is resolved against the provider `base_url`.",
+			Optional: true,
+		},
+
+		"method": schema.StringAttribute{
+			Description: "The HTTP method for the request.",
+			Optional:    true,
+		},
+
+		"request_headers": schema.MapAttribute{
+			Description: "A map of request header field names and values. " +
+				"These are merged with the provider `request_headers`, replacing any header of the same name.",
+			ElementType: types.StringType,
+			Optional:    true,
+		},
+
+		"request_body": schema.StringAttribute{
+			Description: "The request body as a string.",
+			Optional:    true,
+		},
+	}
+}
+
+// requiresReplaceWithoutUpdate requires the resource to be replaced when the
+// create request changes and there is no update request to apply the change.
+func requiresReplaceWithoutUpdate(ctx context.Context, req planmodifier.ObjectRequest, resp *objectplanmodifier.RequiresReplaceIfFuncResponse) {
+	var update types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("update"), &update)...)
+
+	resp.RequiresReplace = update.IsNull()
+}
+
+func (r *httpRequestResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model httpRequestResourceModel
+	diags := req.Plan.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	requestURL, response, body, diags := r.send(ctx, path.Root("create"), model.Create, http.MethodPost, "")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.ID = types.StringValue(requestURL)
+
+	// The request URL is not a usable id when id_attribute is set, as it
+	// would expand the id placeholder of the read, update and delete requests
+	// to the wrong object, so the resource is not saved without its id.
+	if !model.IDAttribute.IsNull() {
+		id, err := jsonAttribute(body, model.IDAttribute.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("id_attribute"),
+				"Error reading resource id",
+				fmt.Sprintf("Error reading %q from the create response: %s. The object created by the request to %s "+
+					"is not saved in the state and must be deleted manually.", model.IDAttribute.ValueString(), err, requestURL),
+			)
+			return
+		}
+
+		model.ID = types.StringValue(id)
+	}
+
+	resp.Diagnostics.Append(model.setResponse(ctx, response, body)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *httpRequestResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model httpRequestResourceModel
+	diags := req.State.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if model.Read == nil {
+		return
+	}
+
+	_, response, body, diags := r.send(ctx, path.Root("read"), model.Read, http.MethodGet, model.ID.ValueString())
+
+	if response != nil && (response.StatusCode == http.StatusNotFound || response.StatusCode == http.StatusGone) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(model.setResponse(ctx, response, body)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *httpRequestResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model, state httpRequestResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.StatusCode = state.StatusCode
+	model.ResponseBody = state.ResponseBody
+	model.ResponseHeaders = state.ResponseHeaders
+
+	// Changes to the read and delete requests only take effect in later
+	// operations, so the update request is only sent when it or the create
+	// request changes.
+	if model.Update != nil && (!model.Create.equal(state.Create) || !model.Update.equal(state.Update)) {
+		_, response, body, diags := r.send(ctx, path.Root("update"), model.Update, http.MethodPut, model.ID.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.Append(model.setResponse(ctx, response, body)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	diags := resp.State.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *httpRequestResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model httpRequestResourceModel
+	diags := req.State.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if model.Delete == nil {
+		return
+	}
+
+	_, response, _, diags := r.send(ctx, path.Root("delete"), model.Delete, http.MethodDelete, model.ID.ValueString())
+
+	if response != nil && (response.StatusCode == http.StatusNotFound || response.StatusCode == http.StatusGone) {
+		return
+	}
+
+	resp.Diagnostics.Append(diags...)
+}
+
+// send sends the request described by the given block, replacing the id
+// placeholder with id. It returns the request URL and the response along with
+// its body. A response without a 2xx status code is returned together with an
+// error diagnostic.
+func (r *httpRequestResource) send(ctx context.Context, blockPath path.Path, model *httpRequestModel, defaultMethod, id string) (string, *http.Response, []byte, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if model == nil || model.URL.IsNull() {
+		diags.AddAttributeError(
+			blockPath.AtName("url"),
+			"Missing request URL",
+			fmt.Sprintf("The %s request must set url.", blockPath),
+		)
+		return "", nil, nil, diags
+	}
+
+	requestURL, err := r.providerData.resolveURL(strings.ReplaceAll(model.URL.ValueString(), idPlaceholder, id))
+	if err != nil {
+		diags.AddAttributeError(
+			blockPath.AtName("url"),
+			"Error resolving URL",
+			fmt.Sprintf("Error resolving URL against the provider base_url: %s", err),
+		)
+		return "", nil, nil, diags
+	}
+
+	method := model.Method.ValueString()
+	if method == "" {
+		method = defaultMethod
+	}
+
+	requestBody := strings.NewReader(strings.ReplaceAll(model.RequestBody.ValueString(), idPlaceholder, id))
+
+	tr, diags := r.providerData.newTransport(types.StringNull(), types.BoolNull())
+	if diags.HasError() {
+		return "", nil, nil, diags
+	}
+
+	client := &http.Client{
+		Transport: tr,
+		Timeout:   r.providerData.requestTimeout,
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, requestURL, requestBody)
+	if err != nil {
+		diags.AddError(
+			"Error creating request",
+			fmt.Sprintf("Error creating %s request: %s", blockPath, err),
+		)
+		return "", nil, nil, diags
+	}
+
+	for name, value := range r.providerData.requestHeaders {
+		request.Header.Set(name, value)
+	}
+
+	requestHeaders := map[string]string{}
+	diags.Append(model.RequestHeaders.ElementsAs(ctx, &requestHeaders, false)...)
+	if diags.HasError() {
+		return "", nil, nil, diags
+	}
+
+	for name, value := range requestHeaders {
+		request.Header.Set(name, value)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		diags.AddError(
+			"Error making request",
+			fmt.Sprintf("Error making %s request: %s", blockPath, err),
+		)
+		return "", nil, nil, diags
+	}
+
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		diags.AddError(
+			"Error reading response body",
+			fmt.Sprintf("Error reading %s response body: %s", blockPath, err),
+		)
+		return "", nil, nil, diags
+	}
+
+	successCodes := []string{"2xx"}
+	if !statusCodeMatches(successCodes, response.StatusCode) {
+		diags.AddError(
+			"Unexpected response status code",
+			fmt.Sprintf("The %s request failed. %s", blockPath, unexpectedStatusDetail(successCodes, response, body, nil, false)),
+		)
+	}
+
+	return requestURL, response, body, diags
+}
+
+// jsonAttribute returns the value of the field at the given dot separated
+// path in the JSON document.
+func jsonAttribute(document []byte, attributePath string) (string, error) {
+	var value interface{}
+	if err := json.Unmarshal(document, &value); err != nil {
+		return "", fmt.Errorf("response body is not valid JSON: %w", err)
+	}
+
+	for _, name := range strings.Split(attributePath, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("expected an object containing %q", name)
+		}
+
+		if value, ok = object[name]; !ok {
+			return "", fmt.Errorf("field %q not found", name)
+		}
+	}
+
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case float64, bool:
+		return fmt.Sprint(value), nil
+	default:
+		return "", fmt.Errorf("expected a string, number or boolean, got %T", value)
+	}
+}
+
+type httpRequestResourceModel struct {
+	ID              types.String      `tfsdk:"id"`
+	IDAttribute     types.String      `tfsdk:"id_attribute"`
+	StatusCode      types.Int64       `tfsdk:"status_code"`
+	ResponseBody    types.String      `tfsdk:"response_body"`
+	ResponseHeaders types.Map         `tfsdk:"response_headers"`
+	Create          *httpRequestModel `tfsdk:"create"`
+	Read            *httpRequestModel `tfsdk:"read"`
+	Update          *httpRequestModel `tfsdk:"update"`
+	Delete          *httpRequestModel `tfsdk:"delete"`
+}
+
+// setResponse stores the response in the computed attributes of the model.
+func (m *httpRequestResourceModel) setResponse(ctx context.Context, response *http.Response, body []byte) diag.Diagnostics {
+	responseHeaders := make(map[string]string)
+	for k, v := range response.Header {
+		// Concatenate according to RFC2616
+		// cf. https://www.w3.org/Protocols/rfc2616/rfc2616-sec4.html#sec4.2
+		responseHeaders[k] = strings.Join(v, ", ")
+	}
+
+	respHeadersState, diags := types.MapValueFrom(ctx, types.StringType, responseHeaders)
+	if diags.HasError() {
+		return diags
+	}
+
+	m.StatusCode = types.Int64Value(int64(response.StatusCode))
+	m.ResponseBody = types.StringValue(string(body))
+	m.ResponseHeaders = respHeadersState
+
+	return diags
+}
+
+type httpRequestModel struct {
+	URL            types.String `tfsdk:"url"`
+	Method         types.String `tfsdk:"method"`
+	RequestHeaders types.Map    `tfsdk:"request_headers"`
+	RequestBody    types.String `tfsdk:"request_body"`
+}
+
+func (m *httpRequestModel) equal(other *httpRequestModel) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+
+	return m.URL.Equal(other.URL) &&
+		m.Method.Equal(other.Method) &&
+		m.RequestHeaders.Equal(other.RequestHeaders) &&
+		m.RequestBody.Equal(other.RequestBody)
+}
@@ -0,0 +1,308 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestResource_HttpRequest(t *testing.T) {
+	api := newTestItemsAPI()
+	defer api.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		CheckDestroy: func(_ *terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+
+			if len(api.items) != 0 {
+				return fmt.Errorf("expected all items to be deleted, got %v", api.items)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							resource "http_request" "test" {
+								id_attribute = "data.id"
+
+								create {
+									url          = "%[1]s/items"
+									request_body = "created"
+								}
+
+								read {
+									url = "%[1]s/items/{id}"
+								}
+
+								update {
+									url          = "%[1]s/items/{id}"
+									request_body = "created"
+								}
+
+								delete {
+									url = "%[1]s/items/{id}"
+								}
+							}`, api.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("http_request.test", "id", "1"),
+					resource.TestCheckResourceAttr("http_request.test", "status_code", "201"),
+					resource.TestCheckResourceAttr("http_request.test", "response_body", `{"data":{"id":1}}`),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+							resource "http_request" "test" {
+								id_attribute = "data.id"
+
+								create {
+									url          = "%[1]s/items"
+									request_body = "created"
+								}
+
+								read {
+									url = "%[1]s/items/{id}"
+								}
+
+								update {
+									url          = "%[1]s/items/{id}"
+									request_body = "updated"
+								}
+
+								delete {
+									url = "%[1]s/items/{id}"
+								}
+							}`, api.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("http_request.test", "id", "1"),
+					resource.TestCheckResourceAttr("http_request.test", "status_code", "200"),
+					resource.TestCheckResourceAttr("http_request.test", "response_body", "updated"),
+				),
+			},
+			{
+				// The item is deleted outside of Terraform, so it is created again.
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					delete(api.items, "1")
+				},
+				Config: fmt.Sprintf(`
+							resource "http_request" "test" {
+								id_attribute = "data.id"
+
+								create {
+									url          = "%[1]s/items"
+									request_body = "created"
+								}
+
+								read {
+									url = "%[1]s/items/{id}"
+								}
+
+								update {
+									url          = "%[1]s/items/{id}"
+									request_body = "updated"
+								}
+
+								delete {
+									url = "%[1]s/items/{id}"
+								}
+							}`, api.server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("http_request.test", "id", "2"),
+					resource.TestCheckResourceAttr("http_request.test", "status_code", "201"),
+				),
+			},
+		},
+	})
+}
+
+func TestResource_HttpRequestCreateFailed(t *testing.T) {
+	api := newTestItemsAPI()
+	defer api.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							resource "http_request" "test" {
+								create {
+									url = "%s/unknown"
+								}
+							}`, api.server.URL),
+				ExpectError: regexp.MustCompile(`The create request failed`),
+			},
+		},
+	})
+}
+
+func TestResource_HttpRequestMissingID(t *testing.T) {
+	api := newTestItemsAPI()
+	defer api.server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		// The created item is not saved in the state without its id, so that
+		// the delete request is not sent to the URL of the create request.
+		CheckDestroy: func(_ *terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+
+			if len(api.items) != 1 {
+				return fmt.Errorf("expected the created item to be left, got %v", api.items)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+							resource "http_request" "test" {
+								id_attribute = "data.name"
+
+								create {
+									url = "%[1]s/items"
+								}
+
+								delete {
+									url = "%[1]s/items/{id}"
+								}
+							}`, api.server.URL),
+				ExpectError: regexp.MustCompile(`Error reading resource id`),
+			},
+		},
+	})
+}
+
+func TestResource_HttpRequestMissingURL(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+							resource "http_request" "test" {
+								create {
+									method = "POST"
+								}
+							}`,
+				ExpectError: regexp.MustCompile(`Attribute "create.url" must be specified when "create" is specified`),
+			},
+		},
+	})
+}
+
+func TestJSONAttribute(t *testing.T) {
+	testCases := map[string]struct {
+		document    string
+		path        string
+		expected    string
+		expectError bool
+	}{
+		"string": {
+			document: `{"id": "abc"}`,
+			path:     "id",
+			expected: "abc",
+		},
+		"number": {
+			document: `{"data": {"id": 42}}`,
+			path:     "data.id",
+			expected: "42",
+		},
+		"missing": {
+			document:    `{"data": {}}`,
+			path:        "data.id",
+			expectError: true,
+		},
+		"object": {
+			document:    `{"data": {"id": {}}}`,
+			path:        "data.id",
+			expectError: true,
+		},
+		"invalid": {
+			document:    `not json`,
+			path:        "id",
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := jsonAttribute([]byte(testCase.document), testCase.path)
+
+			if testCase.expectError != (err != nil) {
+				t.Fatalf("expected error %t, got %v", testCase.expectError, err)
+			}
+
+			if actual != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+// testItemsAPI is a minimal REST API which stores items in memory, for
+// exercising the lifecycle of the http_request resource.
+type testItemsAPI struct {
+	server *httptest.Server
+
+	mu     sync.Mutex
+	items  map[string]string
+	nextID int
+}
+
+func newTestItemsAPI() *testItemsAPI {
+	api := &testItemsAPI{
+		items: map[string]string{},
+	}
+
+	api.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "text/plain")
+
+		if r.URL.Path == "/items" && r.Method == http.MethodPost {
+			api.nextID++
+			id := fmt.Sprint(api.nextID)
+			api.items[id] = string(body)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintf(w, `{"data":{"id":%s}}`, id)
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/items/")
+		item, ok := api.items[id]
+		if !strings.HasPrefix(r.URL.Path, "/items/") || !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(item))
+		case http.MethodPut:
+			api.items[id] = string(body)
+			_, _ = w.Write(body)
+		case http.MethodDelete:
+			delete(api.items, id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+
+	return api
+}
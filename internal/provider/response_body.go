@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+)
+
+// defaultMaxResponseBytes limits the size of the response body read into
+// memory and stored in the state, unless max_response_bytes is set.
+const defaultMaxResponseBytes = 100 * 1024 * 1024
+
+// responseTooLargeError is returned when the response body is larger than the
+// limit and is not truncated.
+type responseTooLargeError struct {
+	limit         int64
+	contentLength int64
+}
+
+func (e *responseTooLargeError) Error() string {
+	if e.contentLength >= 0 {
+		return fmt.Sprintf("response body of %d bytes, as given by Content-Length, is larger than the limit of %d bytes",
+			e.contentLength, e.limit)
+	}
+
+	return fmt.Sprintf("response body is larger than the limit of %d bytes", e.limit)
+}
+
+// readResponseBody reads at most limit bytes of the response body, and
+// reports whether it was truncated. Unless truncate is true, a larger body
+// results in a *responseTooLargeError, which is returned before reading the
+// body when the server sends a Content-Length.
+func readResponseBody(response *http.Response, limit int64, truncate bool) ([]byte, bool, error) {
+	// The Content-Length of a response to a HEAD request is that of the body
+	// which would have been sent.
+	if !truncate && response.ContentLength > limit && response.Request.Method != http.MethodHead {
+		return nil, false, &responseTooLargeError{limit: limit, contentLength: response.ContentLength}
+	}
+
+	// One byte more than the limit is read to tell whether the body is larger,
+	// which it cannot be with the largest limit.
+	readLimit := limit
+	if readLimit < math.MaxInt64 {
+		readLimit++
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, readLimit))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if int64(len(body)) <= limit {
+		return body, false, nil
+	}
+
+	if !truncate {
+		return nil, false, &responseTooLargeError{limit: limit, contentLength: -1}
+	}
+
+	return body[:limit], true, nil
+}
+
+// discardResponseBody drains and closes the body of a response which is not
+// returned, so that the underlying connection can be reused.
+func discardResponseBody(response *http.Response) {
+	_, _ = io.Copy(io.Discard, response.Body)
+	response.Body.Close()
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestReadResponseBody(t *testing.T) {
+	testCases := map[string]struct {
+		method            string
+		body              string
+		contentLength     int64
+		limit             int64
+		truncate          bool
+		expected          string
+		expectedTruncated bool
+		expectTooLarge    bool
+	}{
+		"within-limit": {
+			body:          "hello",
+			contentLength: 5,
+			limit:         5,
+			expected:      "hello",
+		},
+		"within-limit-unknown-length": {
+			body:          "hello",
+			contentLength: -1,
+			limit:         5,
+			expected:      "hello",
+		},
+		"content-length-too-large": {
+			body:           "",
+			contentLength:  6,
+			limit:          5,
+			expectTooLarge: true,
+		},
+		"too-large-unknown-length": {
+			body:           "hello world",
+			contentLength:  -1,
+			limit:          5,
+			expectTooLarge: true,
+		},
+		"head-content-length": {
+			method:        http.MethodHead,
+			body:          "",
+			contentLength: 1000,
+			limit:         5,
+			expected:      "",
+		},
+		"truncated": {
+			body:              "hello world",
+			contentLength:     11,
+			limit:             5,
+			truncate:          true,
+			expected:          "hello",
+			expectedTruncated: true,
+		},
+		"max-limit": {
+			body:          "hello",
+			contentLength: -1,
+			limit:         math.MaxInt64,
+			expected:      "hello",
+		},
+		"not-truncated": {
+			body:          "hello",
+			contentLength: 5,
+			limit:         5,
+			truncate:      true,
+			expected:      "hello",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			method := testCase.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			response := &http.Response{
+				Body:          io.NopCloser(strings.NewReader(testCase.body)),
+				ContentLength: testCase.contentLength,
+				Request:       &http.Request{Method: method},
+			}
+
+			actual, truncated, err := readResponseBody(response, testCase.limit, testCase.truncate)
+
+			if testCase.expectTooLarge {
+				var tooLarge *responseTooLargeError
+				if !errors.As(err, &tooLarge) {
+					t.Fatalf("expected responseTooLargeError, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if string(actual) != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, actual)
+			}
+
+			if truncated != testCase.expectedTruncated {
+				t.Errorf("expected truncated %t, got %t", testCase.expectedTruncated, truncated)
+			}
+		})
+	}
+}
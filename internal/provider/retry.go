@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	defaultRetryMinDelay = 1 * time.Second
+	defaultRetryMaxDelay = 30 * time.Second
+)
+
+// Transport error classes which can be listed in retry.transport_errors.
+const (
+	transportErrorConnectionRefused = "connection_refused"
+	transportErrorConnectionReset   = "connection_reset"
+	transportErrorDNS               = "dns"
+	transportErrorEOF               = "eof"
+	transportErrorTimeout           = "timeout"
+)
+
+var (
+	defaultRetryStatusCodes = []int64{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+
+	defaultRetryTransportErrors = []string{
+		transportErrorConnectionRefused,
+		transportErrorConnectionReset,
+		transportErrorEOF,
+		transportErrorTimeout,
+	}
+)
+
+// retryPolicy decides whether, and after how long, a failed request is
+// sent again. The zero value sends every request exactly once.
+type retryPolicy struct {
+	maxRetries      int
+	minDelay        time.Duration
+	maxDelay        time.Duration
+	statusCodes     map[int]bool
+	transportErrors map[string]bool
+}
+
+// newRetryPolicy builds the retry policy for the given retry block, which may
+// be nil when the block is not configured.
+func newRetryPolicy(ctx context.Context, model *retryModel) (*retryPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	policy := &retryPolicy{
+		minDelay: defaultRetryMinDelay,
+		maxDelay: defaultRetryMaxDelay,
+	}
+
+	if model == nil {
+		return policy, diags
+	}
+
+	policy.maxRetries = int(model.Attempts.ValueInt64())
+
+	if !model.MinDelay.IsNull() {
+		policy.minDelay = time.Duration(model.MinDelay.ValueInt64()) * time.Millisecond
+	}
+
+	if !model.MaxDelay.IsNull() {
+		policy.maxDelay = time.Duration(model.MaxDelay.ValueInt64()) * time.Millisecond
+	}
+
+	if policy.minDelay > policy.maxDelay {
+		policy.minDelay = policy.maxDelay
+	}
+
+	statusCodes := defaultRetryStatusCodes
+	if !model.StatusCodes.IsNull() {
+		diags.Append(model.StatusCodes.ElementsAs(ctx, &statusCodes, false)...)
+	}
+
+	policy.statusCodes = make(map[int]bool, len(statusCodes))
+	for _, code := range statusCodes {
+		policy.statusCodes[int(code)] = true
+	}
+
+	transportErrors := defaultRetryTransportErrors
+	if !model.TransportErrors.IsNull() {
+		diags.Append(model.TransportErrors.ElementsAs(ctx, &transportErrors, false)...)
+	}
+
+	policy.transportErrors = make(map[string]bool, len(transportErrors))
+	for _, class := range transportErrors {
+		policy.transportErrors[class] = true
+	}
+
+	return policy, diags
+}
+
+// do sends the request, retrying according to the policy. It returns the
+// last response or error along with the number of attempts made. When the
+// last attempt results in a retryable status code, the response is returned
+// without an error so the caller can inspect it.
+func (p *retryPolicy) do(ctx context.Context, client *http.Client, request *http.Request) (*http.Response, int, error) {
+	for attempt := 1; ; attempt++ {
+		response, err := client.Do(request)
+
+		if attempt > p.maxRetries {
+			return response, attempt, err
+		}
+
+		var delay time.Duration
+
+		switch {
+		case err != nil:
+			if !p.transportErrors[transportErrorClass(err)] {
+				return nil, attempt, err
+			}
+			delay = p.backoff(attempt)
+		case p.statusCodes[response.StatusCode]:
+			delay = p.backoff(attempt)
+			if retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After")); ok {
+				delay = retryAfter
+			}
+			if delay > p.maxDelay {
+				delay = p.maxDelay
+			}
+
+			discardResponseBody(response)
+		default:
+			return response, attempt, nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, attempt, ctx.Err()
+		case <-timer.C:
+		}
+
+		if request.GetBody != nil {
+			body, err := request.GetBody()
+			if err != nil {
+				return nil, attempt, err
+			}
+			request.Body = body
+		}
+	}
+}
+
+// backoff returns the exponential delay before the given retry, with jitter
+// spreading the delay across its upper half so that concurrent clients do not
+// retry in lockstep.
+func (p *retryPolicy) backoff(attempt int) time.Duration {
+	delay := p.maxDelay
+	if shift := attempt - 1; shift < 32 {
+		if exp := p.minDelay << shift; exp > 0 && exp < p.maxDelay {
+			delay = exp
+		}
+	}
+
+	if delay <= 1 {
+		return delay
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// parseRetryAfter parses a Retry-After header value, which is either a number
+// of seconds or an HTTP date.
+// See https://datatracker.ietf.org/doc/html/rfc7231#section-7.1.3
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// transportErrorClass maps an error returned by http.Client.Do onto one of
+// the transport error classes, or an empty string when it matches none.
+func transportErrorClass(err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ""
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return transportErrorConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		return transportErrorConnectionReset
+	case errors.As(err, &dnsErr):
+		return transportErrorDNS
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return transportErrorEOF
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return transportErrorTimeout
+	}
+
+	return ""
+}
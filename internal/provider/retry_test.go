@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := &retryPolicy{
+		minDelay: 100 * time.Millisecond,
+		maxDelay: time.Second,
+	}
+
+	testCases := map[int]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		3:  400 * time.Millisecond,
+		4:  800 * time.Millisecond,
+		5:  time.Second,
+		64: time.Second,
+	}
+
+	for attempt, expected := range testCases {
+		delay := policy.backoff(attempt)
+		if delay < expected/2 || delay > expected {
+			t.Errorf("attempt %d: expected delay between %s and %s, got %s", attempt, expected/2, expected, delay)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	testCases := map[string]struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		"empty": {
+			value: "",
+		},
+		"seconds": {
+			value:    "120",
+			expected: 2 * time.Minute,
+			ok:       true,
+		},
+		"negative": {
+			value: "-1",
+		},
+		"past-date": {
+			value:    time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat),
+			expected: 0,
+			ok:       true,
+		},
+		"invalid": {
+			value: "soon",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			delay, ok := parseRetryAfter(testCase.value)
+			if ok != testCase.ok || delay != testCase.expected {
+				t.Errorf("expected (%s, %t), got (%s, %t)", testCase.expected, testCase.ok, delay, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package provider
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	awsSigV4Algorithm  = "AWS4-HMAC-SHA256"
+	awsSigV4TimeFormat = "20060102T150405Z"
+	awsSigV4DateFormat = "20060102"
+)
+
+type awsSigV4Model struct {
+	Region       types.String `tfsdk:"region"`
+	Service      types.String `tfsdk:"service"`
+	AccessKey    types.String `tfsdk:"access_key"`
+	SecretKey    types.String `tfsdk:"secret_key"`
+	SessionToken types.String `tfsdk:"session_token"`
+}
+
+// awsSigV4Signer signs requests with AWS Signature Version 4.
+// See https://docs.aws.amazon.com/IAM/latest/UserGuide/create-signed-request.html
+type awsSigV4Signer struct {
+	region       string
+	service      string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+// newAWSSigV4Signer builds the signer for the aws_sigv4 block. The region and
+// credentials which are not configured are read from the standard AWS
+// environment variables.
+func newAWSSigV4Signer(model *awsSigV4Model) (*awsSigV4Signer, error) {
+	signer := &awsSigV4Signer{
+		region:       model.Region.ValueString(),
+		service:      model.Service.ValueString(),
+		accessKey:    model.AccessKey.ValueString(),
+		secretKey:    model.SecretKey.ValueString(),
+		sessionToken: model.SessionToken.ValueString(),
+	}
+
+	if signer.region == "" {
+		signer.region = os.Getenv("AWS_REGION")
+	}
+	if signer.region == "" {
+		signer.region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if signer.region == "" {
+		return nil, errors.New("no AWS region found, set region or the AWS_REGION environment variable")
+	}
+
+	if model.AccessKey.IsNull() {
+		signer.accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		signer.secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		signer.sessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if signer.accessKey == "" || signer.secretKey == "" {
+		return nil, errors.New("no AWS credentials found, set access_key and secret_key or the " +
+			"AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables")
+	}
+
+	return signer, nil
+}
+
+// sign adds the signature to the request, which is signed as of the given
+// time. The body of the request is read to compute its hash, and replaced.
+func (s *awsSigV4Signer) sign(req *http.Request, now time.Time) error {
+	payloadHash, err := payloadSHA256(req)
+	if err != nil {
+		return err
+	}
+
+	now = now.UTC()
+	req.Header.Set("X-Amz-Date", now.Format(awsSigV4TimeFormat))
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+	if s.service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	signedHeaders, canonicalHeaders := awsCanonicalHeaders(req)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsCanonicalURI(req.URL, s.service != "s3"),
+		awsCanonicalQuery(req.URL),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{now.Format(awsSigV4DateFormat), s.region, s.service, "aws4_request"}, "/")
+
+	stringToSign := strings.Join([]string{
+		awsSigV4Algorithm,
+		now.Format(awsSigV4TimeFormat),
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := []byte("AWS4" + s.secretKey)
+	for _, part := range []string{now.Format(awsSigV4DateFormat), s.region, s.service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsSigV4Algorithm,
+		s.accessKey,
+		scope,
+		signedHeaders,
+		hex.EncodeToString(hmacSHA256(key, stringToSign)),
+	))
+
+	return nil
+}
+
+// payloadSHA256 returns the hex encoded SHA-256 hash of the request body.
+func payloadSHA256(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return hexSHA256(nil), nil
+	}
+
+	if req.GetBody == nil {
+		return "", errors.New("request body cannot be read to compute its hash")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	payload, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+
+	return hexSHA256(payload), nil
+}
+
+// awsCanonicalURI returns the URI encoded path of the URL. All services but
+// S3 expect the path to be encoded twice.
+func awsCanonicalURI(u *url.URL, encodeTwice bool) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+
+	if !encodeTwice {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = awsURIEncode(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// awsCanonicalQuery returns the query parameters of the URL, URI encoded and
+// sorted by name and value.
+func awsCanonicalQuery(u *url.URL) string {
+	var params []string
+	for name, values := range u.Query() {
+		for _, value := range values {
+			params = append(params, awsURIEncode(name)+"="+awsURIEncode(value))
+		}
+	}
+	sort.Strings(params)
+
+	return strings.Join(params, "&")
+}
+
+// awsCanonicalHeaders returns the names of the signed headers and their
+// canonical form. The Host and Content-Type headers are signed along with all
+// X-Amz-* headers.
+func awsCanonicalHeaders(req *http.Request) (string, string) {
+	headers := map[string]string{
+		"host": req.Host,
+	}
+	if req.Host == "" {
+		headers["host"] = req.URL.Host
+	}
+
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name != "content-type" && !strings.HasPrefix(name, "x-amz-") {
+			continue
+		}
+
+		trimmed := make([]string, len(values))
+		for i, value := range values {
+			trimmed[i] = strings.Join(strings.Fields(value), " ")
+		}
+		headers[name] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	return strings.Join(names, ";"), canonical.String()
+}
+
+// awsURIEncode encodes every byte except the unreserved characters of
+// RFC 3986, as required by Signature Version 4.
+func awsURIEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
+
+func hexSHA256(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
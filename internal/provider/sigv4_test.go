@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The test cases are taken from the AWS Signature Version 4 test suite.
+func TestAWSSigV4Signer(t *testing.T) {
+	signer := &awsSigV4Signer{
+		region:    "us-east-1",
+		service:   "service",
+		accessKey: "AKIDEXAMPLE",
+		secretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		method   string
+		url      string
+		headers  map[string]string
+		body     string
+		expected string
+	}{
+		"get-vanilla": {
+			method:   "GET",
+			url:      "https://example.amazonaws.com/",
+			expected: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		"get-vanilla-query-order-key-case": {
+			method:   "GET",
+			url:      "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			expected: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		"post-x-www-form-urlencoded": {
+			method:   "POST",
+			url:      "https://example.amazonaws.com/",
+			headers:  map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			body:     "Param1=value1",
+			expected: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature=ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(testCase.method, testCase.url, strings.NewReader(testCase.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for name, value := range testCase.headers {
+				req.Header.Set(name, value)
+			}
+
+			if err := signer.sign(req, now); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if actual := req.Header.Get("Authorization"); actual != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, actual)
+			}
+		})
+	}
+}
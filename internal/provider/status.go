@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxStatusErrorBodyBytes is the number of bytes of the response body which
+// are included in an unexpected status code diagnostic.
+const maxStatusErrorBodyBytes = 1024
+
+// statusCodePattern matches an entry of expected_status_codes, which is
+// either an exact status code such as 200 or a class of status codes such
+// as 2xx.
+var statusCodePattern = regexp.MustCompile(`^[1-5]([0-9]{2}|xx)$`)
+
+// statusCodeMatches reports whether the status code matches any of the given
+// patterns.
+func statusCodeMatches(patterns []string, statusCode int) bool {
+	code := strconv.Itoa(statusCode)
+
+	for _, pattern := range patterns {
+		if pattern == code || strings.HasSuffix(pattern, "xx") && pattern[0] == code[0] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unexpectedStatusDetail describes a response whose status code did not
+// match expected_status_codes, including its headers and the start of its
+// body. The values of the sensitive headers, keyed by canonical name, and the
+// body if it is sensitive, are left out.
+func unexpectedStatusDetail(patterns []string, response *http.Response, body []byte, sensitiveHeaders map[string]bool, sensitiveBody bool) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Expected a status code matching one of %s, got %q.\n", strings.Join(patterns, ", "), response.Status)
+
+	names := make([]string, 0, len(response.Header))
+	for name := range response.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b.WriteString("\nResponse headers:\n")
+	for _, name := range names {
+		if sensitiveHeaders[name] {
+			fmt.Fprintf(&b, "  %s: (sensitive value)\n", name)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s: %s\n", name, strings.Join(response.Header[name], ", "))
+	}
+
+	b.WriteString("\nResponse body:\n")
+	switch {
+	case sensitiveBody:
+		b.WriteString("(sensitive value)")
+	case len(body) > maxStatusErrorBodyBytes:
+		b.WriteString(strings.ToValidUTF8(string(body[:maxStatusErrorBodyBytes]), "�"))
+		fmt.Fprintf(&b, "\n... (%d more bytes)", len(body)-maxStatusErrorBodyBytes)
+	default:
+		b.WriteString(strings.ToValidUTF8(string(body), "�"))
+	}
+
+	return b.String()
+}
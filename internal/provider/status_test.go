@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestStatusCodeMatches(t *testing.T) {
+	testCases := map[string]struct {
+		patterns   []string
+		statusCode int
+		expected   bool
+	}{
+		"exact": {
+			patterns:   []string{"200"},
+			statusCode: 200,
+			expected:   true,
+		},
+		"exact-mismatch": {
+			patterns:   []string{"200"},
+			statusCode: 201,
+		},
+		"class": {
+			patterns:   []string{"2xx"},
+			statusCode: 204,
+			expected:   true,
+		},
+		"class-mismatch": {
+			patterns:   []string{"2xx"},
+			statusCode: 404,
+		},
+		"multiple": {
+			patterns:   []string{"2xx", "404"},
+			statusCode: 404,
+			expected:   true,
+		},
+		"empty": {
+			patterns:   []string{},
+			statusCode: 200,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if actual := statusCodeMatches(testCase.patterns, testCase.statusCode); actual != testCase.expected {
+				t.Errorf("expected %t, got %t", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestUnexpectedStatusDetail(t *testing.T) {
+	response := &http.Response{
+		Status: "401 Unauthorized",
+		Header: http.Header{
+			"Content-Type":     {"text/plain"},
+			"Www-Authenticate": {"Bearer"},
+			"Set-Cookie":       {"session=secret"},
+		},
+	}
+
+	testCases := map[string]struct {
+		sensitiveHeaders map[string]bool
+		sensitiveBody    bool
+		expected         string
+	}{
+		"plain": {
+			expected: "Expected a status code matching one of 2xx, got \"401 Unauthorized\".\n\n" +
+				"Response headers:\n  Content-Type: text/plain\n  Set-Cookie: session=secret\n  Www-Authenticate: Bearer\n\n" +
+				"Response body:\ntoken expired",
+		},
+		"sensitive": {
+			sensitiveHeaders: map[string]bool{"Set-Cookie": true},
+			sensitiveBody:    true,
+			expected: "Expected a status code matching one of 2xx, got \"401 Unauthorized\".\n\n" +
+				"Response headers:\n  Content-Type: text/plain\n  Set-Cookie: (sensitive value)\n  Www-Authenticate: Bearer\n\n" +
+				"Response body:\n(sensitive value)",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := unexpectedStatusDetail([]string{"2xx"}, response, []byte("token expired"), testCase.sensitiveHeaders, testCase.sensitiveBody)
+			if actual != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, actual)
+			}
+		})
+	}
+}
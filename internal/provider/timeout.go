@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// defaultConnectTimeout matches the dialer of http.DefaultTransport.
+const defaultConnectTimeout = 30 * time.Second
+
+// Phases of a request, as reported in timeout diagnostics.
+const (
+	requestPhaseConnect        = "connecting to the server"
+	requestPhaseTLSHandshake   = "performing the TLS handshake"
+	requestPhaseWriteRequest   = "sending the request"
+	requestPhaseResponseHeader = "waiting for the response headers"
+	requestPhaseResponseBody   = "reading the response body"
+)
+
+// newDialer returns a dialer like that of http.DefaultTransport, with the
+// given connect timeout.
+func newDialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}
+}
+
+// requestPhase tracks the phase a request is in, so that a timeout can be
+// reported against the phase in which it occurred.
+type requestPhase struct {
+	mu    sync.Mutex
+	phase string
+}
+
+func newRequestPhase() *requestPhase {
+	return &requestPhase{
+		phase: requestPhaseConnect,
+	}
+}
+
+func (p *requestPhase) set(phase string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.phase = phase
+}
+
+func (p *requestPhase) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.phase
+}
+
+// clientTrace returns the hooks which advance the phase as the request
+// progresses through the transport.
+func (p *requestPhase) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			p.set(requestPhaseConnect)
+		},
+		TLSHandshakeStart: func() {
+			p.set(requestPhaseTLSHandshake)
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				p.set(requestPhaseWriteRequest)
+			}
+		},
+		GotConn: func(httptrace.GotConnInfo) {
+			p.set(requestPhaseWriteRequest)
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			p.set(requestPhaseResponseHeader)
+		},
+	}
+}
+
+// isTimeoutError reports whether err was caused by any of the configured
+// timeouts expiring.
+func isTimeoutError(err error) bool {
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,402 @@
+package provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/youmark/pkcs8"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// PEM block types of the client certificate and key inputs.
+const (
+	pemBlockCertificate         = "CERTIFICATE"
+	pemBlockPrivateKey          = "PRIVATE KEY"
+	pemBlockEncryptedPrivateKey = "ENCRYPTED PRIVATE KEY"
+	pemBlockRSAPrivateKey       = "RSA PRIVATE KEY"
+	pemBlockECPrivateKey        = "EC PRIVATE KEY"
+)
+
+// tlsVersions maps the values of tls_min_version and tls_max_version to TLS
+// versions.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// defaultTLSMinVersion is the minimum TLS version accepted by crypto/tls for
+// clients, used when tls_min_version is not set.
+const defaultTLSMinVersion = "1.2"
+
+// ALPN protocols supported by the transport.
+const (
+	alpnHTTP2 = "h2"
+	alpnHTTP1 = "http/1.1"
+)
+
+// configureTLS applies the TLS version range, cipher suites, server name and
+// ALPN protocols of the data source to the transport.
+func configureTLS(ctx context.Context, tr *http.Transport, model modelV0) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	config := tr.TLSClientConfig
+
+	minVersion := defaultTLSMinVersion
+	if !model.TLSMinVersion.IsNull() {
+		minVersion = model.TLSMinVersion.ValueString()
+		config.MinVersion = tlsVersions[minVersion]
+	}
+
+	// The default maximum version is the highest one, so only a configured
+	// maximum version can be lower than the minimum version.
+	if !model.TLSMaxVersion.IsNull() {
+		maxVersion := model.TLSMaxVersion.ValueString()
+		config.MaxVersion = tlsVersions[maxVersion]
+
+		if tlsVersions[maxVersion] < tlsVersions[minVersion] {
+			detail := fmt.Sprintf("The tls_max_version %s is lower than the tls_min_version %s.", maxVersion, minVersion)
+			if model.TLSMinVersion.IsNull() {
+				detail = fmt.Sprintf("The tls_max_version %s is lower than the default minimum TLS version %s. "+
+					"Set tls_min_version to accept lower versions.", maxVersion, minVersion)
+			}
+
+			diags.AddAttributeError(
+				path.Root("tls_max_version"),
+				"Invalid TLS version range",
+				detail,
+			)
+			return diags
+		}
+	}
+
+	if config.MinVersion != 0 && config.MinVersion < tls.VersionTLS12 {
+		diags.AddAttributeWarning(
+			path.Root("tls_min_version"),
+			"Deprecated TLS version",
+			"TLS 1.0 and 1.1 are deprecated by RFC 8996, as they are no longer considered secure. "+
+				"Only accept them for servers which do not support TLS 1.2.",
+		)
+	}
+
+	if !model.TLSCipherSuites.IsNull() {
+		var names []string
+		diags.Append(model.TLSCipherSuites.ElementsAs(ctx, &names, false)...)
+		if diags.HasError() {
+			return diags
+		}
+
+		cipherSuites, insecure, err := parseCipherSuites(names)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("tls_cipher_suites"),
+				"Invalid cipher suite",
+				err.Error(),
+			)
+			return diags
+		}
+
+		if len(insecure) > 0 {
+			diags.AddAttributeWarning(
+				path.Root("tls_cipher_suites"),
+				"Insecure cipher suites",
+				fmt.Sprintf("The cipher suites %s have known security issues.", strings.Join(insecure, ", ")),
+			)
+		}
+
+		config.CipherSuites = cipherSuites
+	}
+
+	if !model.TLSServerName.IsNull() {
+		config.ServerName = model.TLSServerName.ValueString()
+	}
+
+	if !model.TLSALPNProtocols.IsNull() {
+		var protocols []string
+		diags.Append(model.TLSALPNProtocols.ElementsAs(ctx, &protocols, false)...)
+		if diags.HasError() {
+			return diags
+		}
+
+		config.NextProtos = protocols
+
+		// A non-nil TLSNextProto disables HTTP/2, which would otherwise add h2
+		// to the offered protocols.
+		hasHTTP2 := false
+		for _, protocol := range protocols {
+			hasHTTP2 = hasHTTP2 || protocol == alpnHTTP2
+		}
+
+		if !hasHTTP2 {
+			tr.ForceAttemptHTTP2 = false
+			tr.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		}
+	}
+
+	return diags
+}
+
+// parseCipherSuites returns the IDs of the named cipher suites, along with the
+// names of those which are insecure. TLS 1.3 cipher suites are rejected, as
+// they are not configurable.
+func parseCipherSuites(names []string) ([]uint16, []string, error) {
+	supported := map[string]*tls.CipherSuite{}
+	for _, suite := range tls.CipherSuites() {
+		supported[suite.Name] = suite
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		supported[suite.Name] = suite
+	}
+
+	var ids []uint16
+	var insecure []string
+	for _, name := range names {
+		suite, ok := supported[name]
+		if !ok {
+			var supportedNames []string
+			for supportedName, supportedSuite := range supported {
+				if !isTLS13Only(supportedSuite) {
+					supportedNames = append(supportedNames, supportedName)
+				}
+			}
+			sort.Strings(supportedNames)
+
+			return nil, nil, fmt.Errorf("unsupported cipher suite %q, expected one of: %s", name, strings.Join(supportedNames, ", "))
+		}
+
+		if isTLS13Only(suite) {
+			return nil, nil, fmt.Errorf("the TLS 1.3 cipher suite %q is not configurable, TLS 1.3 cipher suites are always enabled", name)
+		}
+
+		if suite.Insecure {
+			insecure = append(insecure, name)
+		}
+
+		ids = append(ids, suite.ID)
+	}
+
+	return ids, insecure, nil
+}
+
+func isTLS13Only(suite *tls.CipherSuite) bool {
+	return len(suite.SupportedVersions) == 1 && suite.SupportedVersions[0] == tls.VersionTLS13
+}
+
+// newClientCertificate loads the client certificate used for mutual TLS
+// authentication, either from client_cert_pem and client_key_pem or from
+// client_pkcs12_base64. It returns nil when no client certificate is
+// configured.
+func newClientCertificate(model modelV0) (*tls.Certificate, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	password := model.ClientKeyPassword.ValueString()
+
+	if !model.ClientPKCS12.IsNull() {
+		bundle, err := base64.StdEncoding.DecodeString(model.ClientPKCS12.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("client_pkcs12_base64"),
+				"Invalid PKCS#12 bundle",
+				fmt.Sprintf("Error decoding PKCS#12 bundle, which must be base64 encoded: %s", err),
+			)
+			return nil, diags
+		}
+
+		key, leaf, caCerts, err := pkcs12.DecodeChain(bundle, password)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("client_pkcs12_base64"),
+				"Invalid PKCS#12 bundle",
+				fmt.Sprintf("Error decoding PKCS#12 bundle: %s", err),
+			)
+			return nil, diags
+		}
+
+		keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("client_pkcs12_base64"),
+				"Invalid PKCS#12 bundle",
+				fmt.Sprintf("Error loading private key from PKCS#12 bundle: %s", err),
+			)
+			return nil, diags
+		}
+
+		// The leaf certificate must come first in the chain.
+		certBlocks := []*pem.Block{{Type: pemBlockCertificate, Bytes: leaf.Raw}}
+		for _, caCert := range caCerts {
+			certBlocks = append(certBlocks, &pem.Block{Type: pemBlockCertificate, Bytes: caCert.Raw})
+		}
+		keyBlock := &pem.Block{Type: pemBlockPrivateKey, Bytes: keyBytes}
+
+		cert, err := newCertificate(certBlocks, keyBlock, nil)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("client_pkcs12_base64"),
+				"Invalid PKCS#12 bundle",
+				fmt.Sprintf("Error loading client certificate from PKCS#12 bundle: %s", err),
+			)
+			return nil, diags
+		}
+
+		return cert, diags
+	}
+
+	if model.ClientCertificate.IsNull() || model.ClientKey.IsNull() {
+		return nil, diags
+	}
+
+	var certBlocks []*pem.Block
+	rest := []byte(model.ClientCertificate.ValueString())
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type == pemBlockCertificate {
+			certBlocks = append(certBlocks, block)
+		}
+	}
+
+	if len(certBlocks) == 0 {
+		diags.AddAttributeError(
+			path.Root("client_cert_pem"),
+			"Invalid client certificate",
+			"No PEM encoded certificate found in client_cert_pem.",
+		)
+		return nil, diags
+	}
+
+	keyBlock, _ := pem.Decode([]byte(model.ClientKey.ValueString()))
+	if keyBlock == nil {
+		diags.AddAttributeError(
+			path.Root("client_key_pem"),
+			"Invalid client key",
+			"No PEM encoded private key found in client_key_pem.",
+		)
+		return nil, diags
+	}
+
+	if keyBlock.Type == pemBlockEncryptedPrivateKey && password == "" {
+		diags.AddAttributeError(
+			path.Root("client_key_password"),
+			"Missing client key password",
+			"The client key is encrypted, client_key_password must be set to decrypt it.",
+		)
+		return nil, diags
+	}
+
+	cert, err := newCertificate(certBlocks, keyBlock, []byte(password))
+
+	var mismatchErr *keyMismatchError
+	switch {
+	case errors.As(err, &mismatchErr):
+		diags.AddAttributeError(
+			path.Root("client_key_pem"),
+			"Client certificate and key do not match",
+			fmt.Sprintf("The private key in client_key_pem does not belong to the certificate in client_cert_pem: %s", err),
+		)
+		return nil, diags
+	case err != nil:
+		diags.AddAttributeError(
+			path.Root("client_key_pem"),
+			"Invalid client key",
+			fmt.Sprintf("Error loading client key: %s", err),
+		)
+		return nil, diags
+	}
+
+	return cert, diags
+}
+
+// keyMismatchError is returned when the private key does not belong to the
+// leaf certificate.
+type keyMismatchError struct {
+	subject string
+}
+
+func (e *keyMismatchError) Error() string {
+	return fmt.Sprintf("public key of certificate %q does not match the private key", e.subject)
+}
+
+// newCertificate builds a certificate from a chain of PEM certificate blocks,
+// leaf first, and the PEM block of its private key. The password is only used
+// for keys of type ENCRYPTED PRIVATE KEY.
+func newCertificate(certBlocks []*pem.Block, keyBlock *pem.Block, password []byte) (*tls.Certificate, error) {
+	if len(certBlocks) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+
+	cert := &tls.Certificate{}
+	for _, block := range certBlocks {
+		cert.Certificate = append(cert.Certificate, block.Bytes)
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("parsing certificate: %w", err)
+	}
+
+	var key crypto.PrivateKey
+	switch keyBlock.Type {
+	case pemBlockEncryptedPrivateKey:
+		key, err = pkcs8.ParsePKCS8PrivateKey(keyBlock.Bytes, password)
+	case pemBlockPrivateKey, pemBlockRSAPrivateKey, pemBlockECPrivateKey:
+		if _, ok := keyBlock.Headers["DEK-Info"]; ok {
+			return nil, errors.New("legacy PEM encryption is not supported, convert the key to an encrypted PKCS#8 key")
+		}
+		key, err = parsePrivateKey(keyBlock.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", keyBlock.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	publicKey, ok := leaf.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !publicKey.Equal(signer.Public()) {
+		return nil, &keyMismatchError{subject: leaf.Subject.String()}
+	}
+
+	cert.PrivateKey = key
+	cert.Leaf = leaf
+
+	return cert, nil
+}
+
+// parsePrivateKey parses an unencrypted private key in PKCS#1, SEC 1 or
+// PKCS#8 form, in the same order as tls.X509KeyPair.
+func parsePrivateKey(der []byte) (crypto.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("unknown private key format")
+}
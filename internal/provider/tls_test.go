@@ -0,0 +1,302 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/youmark/pkcs8"
+)
+
+// testPKCS12Bundle is a base64 encoded PKCS#12 bundle, protected by the
+// password "test", for a self-signed certificate with the common name
+// terraform-provider-http-client. It was created with:
+//
+//	openssl pkcs12 -export -legacy -in cert.pem -inkey key.pem -passout pass:test
+const testPKCS12Bundle = "" +
+	"MIIDqgIBAzCCA3AGCSqGSIb3DQEHAaCCA2EEggNdMIIDWTCCAk8GCSqGSIb3DQEHBqCCAkAwggI8" +
+	"AgEAMIICNQYJKoZIhvcNAQcBMBwGCiqGSIb3DQEMAQYwDgQIgyZqlgMWVFUCAggAgIICCPbDdLrl" +
+	"RJIG33Z0ClNtsH2keYRxXeXh2iGuJQz4gKb7QM7F4OdIYTYJd7CpRn0yQWwQgVeBM6m9WBIE0gjL" +
+	"J4ljtMOaxRhS7g9Tiot00w1adiGuGG2O29N7syAsX54GGUXJuhx4bzuOf8lNnSp5v0ZCbwm24k+T" +
+	"WSMbzBEyEghxbwkFzMcmPlDFnVrrFVMKkGx4JSUChmCDp794WiHXhQIPmxUzjE53N9As19DrBPWw" +
+	"aEl/nnVNxDlGjKiVu05frQFFPdv4m0UG1lTCvo3avyGnJpAGBBE7s79sJo5GMxB4eBWeDIKjXckH" +
+	"CgQBh4+FQ/ZdsvJUnoiZRFZiTBOsOJN+HcCmgLD5R2PTRfIRPtDtM6OlIGhAmXRgO5Yg2kT2W9Sf" +
+	"7mXNZ49g7xYEZTm1qwTNsf+kGLmBFbGP2e7d3joL3m085RXaZKUjmrA6hhIck7DFoc+WvqvwYnPS" +
+	"gX4Q/O6aBGisB2xDD7qEeaLAq7WoN7ast31kyBrGLOTJoM6kEbkdFCinLfEJj5ZyUxjQ2vqDKNtR" +
+	"HYvLtM0Ie/1hOfhTWMJ6VN6fHEwNFb1KgaE+blYmUxDw97qBsCeRyq19bVw/0vR2915ofcmW9965" +
+	"CbFCSyN89jdlHFE5z6UT262HQJ24pa8IcVkHDcUUH+tOvpOTmVwFISfsXmhQdwoztxfsDwEwWGFy" +
+	"biswggECBgkqhkiG9w0BBwGggfQEgfEwge4wgesGCyqGSIb3DQEMCgECoIG0MIGxMBwGCiqGSIb3" +
+	"DQEMAQMwDgQIwsiYIABzPX4CAggABIGQmoTUf8sYDVdwfXy99smYWiUo34lpfrIUMvFuHon2xPjw" +
+	"EGv4bILzIyxPJ3feQs4Z++fM1Ym2afbXkLKRa7ZUC6D86T8ugP0vhEVP6PApVyIprz984B0P//Lt" +
+	"gSym5WP8yb7M+3vDHAC/+WCfF43zsr++02jbiL5d1HcsWy2bbs3+scFe/lvn+wib4QJuFwSTMSUw" +
+	"IwYJKoZIhvcNAQkVMRYEFAfXj6eztLkeH6L3nmoCyIwEyrsbMDEwITAJBgUrDgMCGgUABBSkf54+" +
+	"Cxdw5Npz0ohvqIsNH5X5eAQIt4js352rPUoCAggA"
+
+// testPKCS12BundleAES is a base64 encoded PKCS#12 bundle, protected by the
+// password "test", for a certificate with the common name
+// terraform-provider-http-client issued by terraform-provider-http-ca, and
+// the CA certificate. It uses the AES-256 and PBKDF2 algorithms, as created
+// by default with OpenSSL 3:
+//
+//	openssl pkcs12 -export -in cert.pem -inkey key.pem -certfile ca.pem -passout pass:test
+const testPKCS12BundleAES = "" +
+	"MIIFrAIBAzCCBWIGCSqGSIb3DQEHAaCCBVMEggVPMIIFSzCCBAIGCSqGSIb3DQEHBqCCA/MwggPv" +
+	"AgEAMIID6AYJKoZIhvcNAQcBMFcGCSqGSIb3DQEFDTBKMCkGCSqGSIb3DQEFDDAcBAicj76A2DqF" +
+	"2wICCAAwDAYIKoZIhvcNAgkFADAdBglghkgBZQMEASoEEGjnsXmAAqlX3WwPkZHnJGKAggOATVgF" +
+	"GtE60mZs1GhDXORc1GnWcNxYn+wC4Inuz/XIcPd2R2TtNq1JtvJAAzXEOu0ZLnypF+g4v5rmn+op" +
+	"uZisCbSDfwh62oGAnPlceUkrfW1vAdmU7NT4S8Sytrfeo8w8k7kpW+k3XqOzIKlnkSBq0tagwp9k" +
+	"M3qu/1ImbgQosx5skWMmu98Z/pv+EWQjxJgxtvBm1t4egno2km01awNajFWnEvhWb4DkKfKRkSja" +
+	"2crHCfzh1I2sq0VF/KWNi7691B5fLPkp8EU83+U2X1DHUiixmWhuXgGYFE2tD/FOJQZdPcguHsuj" +
+	"YcMvNFitYhKZ6ELK7jcXzZGwhJik73iHje4Jo8UE8p3O0ud3ZcPDnTc/Zs1hxLJfbyvUikIvhJY6" +
+	"k2WvOt9J2jXTqFblQj9thr0ItOt0J7k65vQHj+bR3qzyJxl2ngJjV4SsFXdJHiws9FOlc7y/DNBl" +
+	"4EZz53/KCk0oyqhSHqUnjeANrqNgWj9/x6MSvA6zqGJKQ/o4L300OPbhHh9meRjwpwSF2U2SgJA6" +
+	"HzBBy9kKDUbhNqlCoFnxFZUmHoVLCS7NkEyhbcQtz9aB+ZbWDlkgjUWWKpD49Roi0h7Eza4DVI84" +
+	"zlX1bozl1EHL97Pj/2Hef6ZS89/iLrE7iGK3NjMUb6X6r04PvsUTEJa08NXEtaVjYDfTpc3TgpDX" +
+	"Ca1ZgxuF2WHtTJWWtjWnWXe70MCRaovkdhrS7R72grOApuEEsEpyRKvbSaLprXplTDd8L4FDWp1l" +
+	"QMN5bsk+OSxeQm3+FMI8LXM2YA9gcrSHhUS1ArKIHbk5+eooeh95hIu5gm9Vwq9kPJ7BVXHwUE5o" +
+	"V8KWI9bakLhEFIvwEDX4MOWXc0NYv53UdRZDWhVRKFG0FMWEtGE32YDHo9sXbcElvcdDELru/WMb" +
+	"v0X0a8VGu6jX40cpIwoyCUM8bl1biwsjQZItSsknQkW27adHAAdoEl3ZzOEeklbl5/7oKQBM5Qyq" +
+	"3WXbGiDio89ZKBr0gCapYV/9bwpTu2gNo27hmYS9LLRNXMtqAL6T6LpXN8QD5ZLey9hrHSqQe5Bx" +
+	"ErpuIcXnP/OHk6UzyT6TksF4W+6EsagIuXZrwOGFqWU6N/bd9BqSBViBS1CtoXyGYUfjaTDmL7Y9" +
+	"r6X3loiX3s9nG9ESUX05cD0uRRJbjCdCKdiJUp51E2HtqmatNzEwggFBBgkqhkiG9w0BBwGgggEy" +
+	"BIIBLjCCASowggEmBgsqhkiG9w0BDAoBAqCB7zCB7DBXBgkqhkiG9w0BBQ0wSjApBgkqhkiG9w0B" +
+	"BQwwHAQI7jG3S9e7Q1YCAggAMAwGCCqGSIb3DQIJBQAwHQYJYIZIAWUDBAEqBBBN6np3Fr62UWLW" +
+	"JwNp0LC1BIGQY8uWh9kG/4bME3GbhLvO4bqrQPjGhAcdTtzWbSrYrL/Qpo8mrFFsizn0zgC1BVe4" +
+	"NDWlJeJ+3OehfozeQcWz5NVtT+rSRXt1ClN0xGw26358dgrI85JISoHOw8xtsTMBFlujbTAJoM0V" +
+	"XAXIr70zuiPQmt0kH7z8DijP4mqfBPsqYjzXg6D4uS6OnS36g/eNMSUwIwYJKoZIhvcNAQkVMRYE" +
+	"FDd33NVECzKVCdYJPrS9LAiXsN+RMEEwMTANBglghkgBZQMEAgEFAAQguRV4Y7BcX9+E/lw8OkjI" +
+	"1wvEfLY1sLOhlUTgr/nbEpgECFmjq4f8tPfbAgIIAA=="
+
+func TestNewCertificate(t *testing.T) {
+	certPEM, key := testClientCertificate(t, "client")
+	_, otherKey := testClientCertificate(t, "other")
+
+	sec1, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("error marshalling key: %s", err)
+	}
+
+	plain, err := pkcs8.ConvertPrivateKeyToPKCS8(key)
+	if err != nil {
+		t.Fatalf("error marshalling key: %s", err)
+	}
+
+	encrypted, err := pkcs8.ConvertPrivateKeyToPKCS8(key, []byte("password"))
+	if err != nil {
+		t.Fatalf("error encrypting key: %s", err)
+	}
+
+	other, err := pkcs8.ConvertPrivateKeyToPKCS8(otherKey)
+	if err != nil {
+		t.Fatalf("error marshalling key: %s", err)
+	}
+
+	certBlock, _ := pem.Decode([]byte(certPEM))
+
+	testCases := map[string]struct {
+		keyBlock    *pem.Block
+		password    string
+		expectError bool
+		mismatch    bool
+	}{
+		"sec1": {
+			keyBlock: &pem.Block{Type: pemBlockECPrivateKey, Bytes: sec1},
+		},
+		"pkcs8": {
+			keyBlock: &pem.Block{Type: pemBlockPrivateKey, Bytes: plain},
+		},
+		"pkcs8-encrypted": {
+			keyBlock: &pem.Block{Type: pemBlockEncryptedPrivateKey, Bytes: encrypted},
+			password: "password",
+		},
+		"pkcs8-encrypted-wrong-password": {
+			keyBlock:    &pem.Block{Type: pemBlockEncryptedPrivateKey, Bytes: encrypted},
+			password:    "wrong",
+			expectError: true,
+		},
+		"legacy-encrypted": {
+			keyBlock: &pem.Block{
+				Type:    pemBlockECPrivateKey,
+				Headers: map[string]string{"Proc-Type": "4,ENCRYPTED", "DEK-Info": "AES-256-CBC,00"},
+				Bytes:   sec1,
+			},
+			expectError: true,
+		},
+		"mismatch": {
+			keyBlock:    &pem.Block{Type: pemBlockPrivateKey, Bytes: other},
+			expectError: true,
+			mismatch:    true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cert, err := newCertificate([]*pem.Block{certBlock}, testCase.keyBlock, []byte(testCase.password))
+
+			if testCase.expectError {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+
+				var mismatchErr *keyMismatchError
+				if errors.As(err, &mismatchErr) != testCase.mismatch {
+					t.Errorf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if cert.Leaf.Subject.CommonName != "client" {
+				t.Errorf("expected leaf certificate for client, got %q", cert.Leaf.Subject.CommonName)
+			}
+		})
+	}
+}
+
+func TestNewClientCertificate_PKCS12(t *testing.T) {
+	testCases := map[string]struct {
+		bundle    string
+		chainSize int
+	}{
+		"legacy": {
+			bundle:    testPKCS12Bundle,
+			chainSize: 1,
+		},
+		"aes": {
+			bundle:    testPKCS12BundleAES,
+			chainSize: 2,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			model := modelV0{
+				ClientPKCS12:      types.StringValue(testCase.bundle),
+				ClientKeyPassword: types.StringValue("test"),
+			}
+
+			cert, diags := newClientCertificate(model)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if cert.Leaf.Subject.CommonName != "terraform-provider-http-client" {
+				t.Errorf("expected leaf certificate for terraform-provider-http-client, got %q", cert.Leaf.Subject.CommonName)
+			}
+
+			if len(cert.Certificate) != testCase.chainSize {
+				t.Errorf("expected %d certificates in the chain, got %d", testCase.chainSize, len(cert.Certificate))
+			}
+
+			model.ClientKeyPassword = types.StringValue("wrong")
+
+			_, diags = newClientCertificate(model)
+			if !diags.HasError() {
+				t.Fatal("expected error for wrong password, got none")
+			}
+		})
+	}
+}
+
+// testClientCertificate returns a PEM encoded self-signed certificate with the
+// given common name, and its private key.
+func testClientCertificate(t *testing.T, commonName string) (string, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %s", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("error creating certificate: %s", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: pemBlockCertificate, Bytes: der})), key
+}
+
+// testKeyToPEM returns the given private key as a PEM encoded PKCS#8 key.
+func testKeyToPEM(t *testing.T, key *ecdsa.PrivateKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("error marshalling key: %s", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: pemBlockPrivateKey, Bytes: der}))
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	testCases := map[string]struct {
+		names            []string
+		expectedIDs      []uint16
+		expectedInsecure []string
+		expectedErr      bool
+	}{
+		"secure": {
+			names:       []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256"},
+			expectedIDs: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256},
+		},
+		"insecure": {
+			names:            []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_RSA_WITH_RC4_128_SHA"},
+			expectedIDs:      []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_RSA_WITH_RC4_128_SHA},
+			expectedInsecure: []string{"TLS_RSA_WITH_RC4_128_SHA"},
+		},
+		"tls13": {
+			names:       []string{"TLS_AES_128_GCM_SHA256"},
+			expectedErr: true,
+		},
+		"unknown": {
+			names:       []string{"TLS_UNKNOWN"},
+			expectedErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ids, insecure, err := parseCipherSuites(testCase.names)
+			if testCase.expectedErr {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if fmt.Sprint(ids) != fmt.Sprint(testCase.expectedIDs) {
+				t.Errorf("expected cipher suites %v, got %v", testCase.expectedIDs, ids)
+			}
+
+			if fmt.Sprint(insecure) != fmt.Sprint(testCase.expectedInsecure) {
+				t.Errorf("expected insecure cipher suites %v, got %v", testCase.expectedInsecure, insecure)
+			}
+		})
+	}
+}
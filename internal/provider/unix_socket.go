@@ -0,0 +1,17 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+// configureUnixSocket makes the transport send every request over the Unix
+// domain socket at socketPath, whatever the host of its URL, which only
+// supplies the Host header. Proxies are not used.
+func configureUnixSocket(tr *http.Transport, dialer *net.Dialer, socketPath string) {
+	tr.Proxy = nil
+	tr.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "unix", socketPath)
+	}
+}